@@ -5,9 +5,11 @@ go 1.23.0
 toolchain go1.24.6
 
 require (
+	filippo.io/age v1.2.1
 	github.com/charmbracelet/bubbles v0.18.0
 	github.com/charmbracelet/bubbletea v0.26.6
 	github.com/charmbracelet/lipgloss v0.12.1
+	github.com/fsnotify/fsnotify v1.10.1
 	github.com/go-git/go-billy/v5 v5.6.2
 	github.com/go-git/go-git/v5 v5.16.2
 	github.com/pelletier/go-toml v1.9.5
@@ -15,6 +17,7 @@ require (
 	github.com/spf13/cobra v1.8.1
 	github.com/stretchr/testify v1.10.0
 	go.uber.org/mock v0.6.0
+	golang.org/x/term v0.31.0
 )
 
 require (
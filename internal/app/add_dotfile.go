@@ -61,16 +61,41 @@ func (app *App) addDotfileFile(ignores []string) error {
 }
 
 func (app *App) UpdateConfig(dotfileName, cleanedPath string, ignores []string) error {
+	return app.updateConfig(dotfileName, cleanedPath, ignores, false)
+}
+
+// updateConfig implements UpdateConfig, encrypt marks the dotfile for encrypted storage
+// in the same write
+func (app *App) updateConfig(dotfileName, cleanedPath string, ignores []string, encrypt bool) error {
 	app.Config.Dotfiles[dotfileName] = cleanedPath
 
-	if err := config.AddDotfileToConfig(app.ConfigFilePath, dotfileName, cleanedPath, ignores); err != nil {
+	addToConfig := config.AddDotfileToConfig
+	if encrypt {
+		addToConfig = config.AddEncryptedDotfileToConfig
+		if app.Config.DotfilesEncrypt == nil {
+			app.Config.DotfilesEncrypt = make(map[string]bool)
+		}
+		app.Config.DotfilesEncrypt[dotfileName] = true
+	}
+	if err := addToConfig(app.ConfigFilePath, dotfileName, cleanedPath, ignores); err != nil {
 		return fmt.Errorf("error adding dotfile to config: %w", err)
 	}
 
+	if len(ignores) > 0 {
+		if app.Config.DotfilesIgnores == nil {
+			app.Config.DotfilesIgnores = make(map[string][]string)
+		}
+		app.Config.DotfilesIgnores[dotfileName] = ignores
+	}
+
 	if app.Config.Dotfiles == nil {
 		app.Config.Dotfiles = make(map[string]string)
 	}
 
+	if err := app.addDotfileToActiveProfile(dotfileName); err != nil {
+		return fmt.Errorf("error adding dotfile to profile: %w", err)
+	}
+
 	return nil
 }
 
@@ -8,6 +8,8 @@ import (
 
 	"github.com/bnema/gart/internal/config"
 	"github.com/bnema/gart/internal/git"
+	"github.com/bnema/gart/internal/security"
+	"github.com/bnema/gart/internal/system"
 )
 
 type App struct {
@@ -16,8 +18,18 @@ type App struct {
 	Dotfile        Dotfile
 	Config         *config.Config
 	ConfigError    error
-	mu             sync.RWMutex
-	gitRepo        git.GitRepository
+	ActiveProfile  string
+	// ConflictResolution is applied without prompting to dotfiles changed on both sides
+	ConflictResolution string
+	// PromptPolicy answers the prompts without reading stdin, prompts are shown when empty
+	PromptPolicy system.PromptPolicy
+	// JSONOutput reserves stdout for the JSON document of the command
+	JSONOutput bool
+	mu         sync.RWMutex
+	gitRepo    git.GitRepository
+	encrypter  *system.Encrypter
+	manifest   *system.Manifest
+	baseline   *security.Baseline
 }
 
 type Dotfile struct {
@@ -64,7 +76,7 @@ func (app *App) SetGitRepository(repo git.GitRepository) {
 func (app *App) getOrCreateGitRepository() (git.GitRepository, error) {
 	app.mu.Lock()
 	defer app.mu.Unlock()
-	
+
 	if app.gitRepo == nil {
 		repo, err := git.NewRepository(app.StoragePath)
 		if err != nil {
@@ -72,7 +84,7 @@ func (app *App) getOrCreateGitRepository() (git.GitRepository, error) {
 		}
 		app.gitRepo = repo
 	}
-	
+
 	return app.gitRepo, nil
 }
 
@@ -92,10 +104,24 @@ func (app *App) GitInit() error {
 
 // GitCommitChanges commits changes to the Git repository
 func (app *App) GitCommitChanges(action, dotfileName string) error {
+	return app.gitCommit(action, dotfileName, []string{dotfileName})
+}
+
+// gitCommit runs the pre_commit hooks of each of the dotfiles, then commits all the changes of
+// the store with a message for dotfileName, which names the dotfiles of the commit
+func (app *App) gitCommit(action, dotfileName string, dotfiles []string) error {
 	if !app.Config.Settings.GitVersioning {
 		return nil // Git versioning is disabled, so we don't commit
 	}
 
+	for _, name := range dotfiles {
+		hc := app.hookContext(name, "")
+		hc.Action = action
+		if err := app.RunHooks(config.HookPreCommit, hc); err != nil {
+			return err
+		}
+	}
+
 	repo, err := app.getOrCreateGitRepository()
 	if err != nil {
 		return err
@@ -148,11 +174,49 @@ func (app *App) GitCommitChanges(action, dotfileName string) error {
 	return nil
 }
 
+// GitPull fetches the remote of the store and integrates the given branch, the current
+// one when empty, using the given strategy or the configured one when empty
+func (app *App) GitPull(branch, strategy string) error {
+	if !app.Config.Settings.GitVersioning {
+		return fmt.Errorf("git versioning is disabled")
+	}
+
+	if strategy == "" {
+		strategy = app.Config.Settings.Git.PullStrategy
+	}
+	pullStrategy, err := git.ParsePullStrategy(strategy)
+	if err != nil {
+		return err
+	}
+
+	repo, err := app.getOrCreateGitRepository()
+	if err != nil {
+		return err
+	}
+
+	if err := repo.Pull(git.PullOptions{Branch: branch, Strategy: pullStrategy}); err != nil {
+		return fmt.Errorf("failed to pull changes: %w", err)
+	}
+	return nil
+}
+
+// AutoPull pulls the store when auto_pull is enabled, so that reverse sync and
+// deploy write the latest changes of the other machines
+func (app *App) AutoPull() error {
+	if !app.Config.Settings.GitVersioning || !app.Config.Settings.Git.AutoPull {
+		return nil
+	}
+	return app.GitPull("", "")
+}
+
 func (app *App) UpdateDotfileIgnores(name string, ignores []string) error {
 	if _, ok := app.Config.Dotfiles[name]; !ok {
 		return fmt.Errorf("dotfile '%s' not found", name)
 	}
 
 	app.Config.DotfilesIgnores[name] = ignores
-	return config.UpdateDotfileIgnores(app.ConfigFilePath, name, ignores)
+	if err := config.UpdateDotfileIgnores(app.ConfigFilePath, name, ignores); err != nil {
+		return err
+	}
+	return app.UpdateStoreGitignore()
 }
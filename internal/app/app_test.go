@@ -1,6 +1,8 @@
 package app
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/bnema/gart/internal/config"
@@ -262,4 +264,93 @@ func TestApp_SetGitRepository(t *testing.T) {
 	repo, err := app.getOrCreateGitRepository()
 	require.NoError(t, err)
 	assert.Same(t, mockRepo, repo)
-}
\ No newline at end of file
+}
+func TestApp_GitPull_UsesConfiguredStrategy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockGitRepository(ctrl)
+
+	app := &App{
+		Config: &config.Config{
+			Settings: config.SettingsConfig{
+				GitVersioning: true,
+				Git: config.GitConfig{
+					PullStrategy: "rebase",
+				},
+			},
+		},
+		gitRepo: mockRepo,
+	}
+
+	mockRepo.EXPECT().Pull(git.PullOptions{Strategy: git.PullRebase}).Return(nil)
+	assert.NoError(t, app.GitPull("", ""))
+
+	// An explicit strategy and branch override the configuration
+	mockRepo.EXPECT().Pull(git.PullOptions{Branch: "laptop", Strategy: git.PullMerge}).Return(nil)
+	assert.NoError(t, app.GitPull("laptop", "merge"))
+
+	assert.Error(t, app.GitPull("", "octopus"))
+}
+
+func TestApp_AutoPull(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockGitRepository(ctrl)
+
+	app := &App{
+		Config: &config.Config{
+			Settings: config.SettingsConfig{
+				GitVersioning: true,
+			},
+		},
+		gitRepo: mockRepo,
+	}
+
+	// auto_pull disabled, nothing is pulled
+	assert.NoError(t, app.AutoPull())
+
+	app.Config.Settings.Git.AutoPull = true
+	mockRepo.EXPECT().Pull(git.PullOptions{Strategy: git.PullFastForward}).Return(git.ErrNonFastForward)
+	err := app.AutoPull()
+	assert.ErrorIs(t, err, git.ErrNonFastForward)
+}
+
+func TestApp_DotfileLog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockGitRepository(ctrl)
+	storeDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(storeDir, "nvim"), 0755))
+
+	app := &App{
+		Config: &config.Config{
+			Settings: config.SettingsConfig{
+				GitVersioning: true,
+			},
+			Dotfiles: map[string]string{"nvim": "/home/user/.config/nvim"},
+		},
+		StoragePath: storeDir,
+		gitRepo:     mockRepo,
+	}
+
+	commits := []git.CommitInfo{{Hash: "abc1234", Message: "Update nvim"}}
+	mockRepo.EXPECT().Log("nvim", 5).Return(commits, nil)
+	got, err := app.DotfileLog("nvim", 5)
+	require.NoError(t, err)
+	assert.Equal(t, commits, got)
+
+	// No name lists the history of the whole store
+	mockRepo.EXPECT().Log("", 0).Return(nil, nil)
+	_, err = app.DotfileLog("", 0)
+	assert.NoError(t, err)
+
+	_, err = app.DotfileLog("unknown", 0)
+	assert.Error(t, err)
+
+	app.Config.Settings.GitVersioning = false
+	_, err = app.DotfileLog("nvim", 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,208 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bnema/gart/internal/system"
+	"github.com/pelletier/go-toml"
+)
+
+const (
+	backupManifestName = "backup.toml"
+	backupFilesDir     = "files"
+	backupIDFormat     = "20060102-150405.000"
+)
+
+// Backup describes a snapshot of local files taken before gart overwrote or deleted them
+type Backup struct {
+	ID        string    `toml:"id"`
+	Dotfile   string    `toml:"dotfile"`
+	Reason    string    `toml:"reason"`
+	CreatedAt time.Time `toml:"created_at"`
+	Paths     []string  `toml:"paths"`
+}
+
+// backupRoot returns the directory holding all backups
+func (app *App) backupRoot() string {
+	if app.Config.Settings.Backup != nil && app.Config.Settings.Backup.Path != "" {
+		return app.ExpandHomeDir(app.Config.Settings.Backup.Path)
+	}
+	return filepath.Join(filepath.Dir(app.StoragePath), "backups")
+}
+
+// backupsEnabled reports whether local files should be snapshotted before being overwritten
+func (app *App) backupsEnabled() bool {
+	return app.Config.Settings.Backup == nil || app.Config.Settings.Backup.Enabled
+}
+
+// BackupPlanTargets snapshots every existing file a plan would overwrite or delete.
+// It returns nil when there was nothing to back up or backups are disabled.
+func (app *App) BackupPlanTargets(dotfileName, reason string, plan *system.Plan) (*Backup, error) {
+	if !app.backupsEnabled() {
+		return nil, nil
+	}
+
+	var paths []string
+	for _, change := range plan.Changes {
+		if change.Type == system.ChangeCreate {
+			continue
+		}
+		paths = append(paths, change.Target)
+	}
+
+	return app.CreateBackup(dotfileName, reason, paths)
+}
+
+// CreateBackup copies the given local paths into a new timestamped backup
+func (app *App) CreateBackup(dotfileName, reason string, paths []string) (*Backup, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	now := time.Now()
+	backup := &Backup{
+		ID:        now.Format(backupIDFormat) + "-" + dotfileName,
+		Dotfile:   dotfileName,
+		Reason:    reason,
+		CreatedAt: now,
+	}
+	backupDir := filepath.Join(app.backupRoot(), backup.ID)
+
+	for _, path := range paths {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", path, err)
+		}
+
+		dest := filepath.Join(backupDir, backupFilesDir, absPath)
+		if err := os.MkdirAll(filepath.Dir(dest), 0700); err != nil {
+			return nil, fmt.Errorf("failed to create backup directory: %w", err)
+		}
+		if err := system.CopyPath(absPath, dest, nil); err != nil {
+			return nil, fmt.Errorf("failed to back up %s: %w", absPath, err)
+		}
+		backup.Paths = append(backup.Paths, absPath)
+	}
+
+	if len(backup.Paths) == 0 {
+		return nil, nil
+	}
+
+	data, err := toml.Marshal(backup)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode backup manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(backupDir, backupManifestName), data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write backup manifest: %w", err)
+	}
+
+	if err := app.PruneBackups(); err != nil {
+		return backup, fmt.Errorf("failed to prune old backups: %w", err)
+	}
+
+	return backup, nil
+}
+
+// ListBackups returns all backups, newest first
+func (app *App) ListBackups() ([]Backup, error) {
+	entries, err := os.ReadDir(app.backupRoot())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read backup directory: %w", err)
+	}
+
+	var backups []Backup
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		backup, err := app.loadBackup(entry.Name())
+		if err != nil {
+			continue // Skip incomplete or foreign directories
+		}
+		backups = append(backups, *backup)
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].CreatedAt.After(backups[j].CreatedAt)
+	})
+	return backups, nil
+}
+
+// RestoreBackup copies the files of a backup back to their original locations
+func (app *App) RestoreBackup(id string) (*Backup, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) {
+		return nil, fmt.Errorf("invalid backup id '%s'", id)
+	}
+
+	backup, err := app.loadBackup(id)
+	if err != nil {
+		return nil, fmt.Errorf("backup '%s' not found: %w", id, err)
+	}
+
+	backupDir := filepath.Join(app.backupRoot(), backup.ID)
+	for _, path := range backup.Paths {
+		src := filepath.Join(backupDir, backupFilesDir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create parent directory: %w", err)
+		}
+		if err := system.CopyPath(src, path, nil); err != nil {
+			return nil, fmt.Errorf("failed to restore %s: %w", path, err)
+		}
+	}
+
+	return backup, nil
+}
+
+// PruneBackups deletes backups beyond the configured count or age
+func (app *App) PruneBackups() error {
+	cfg := app.Config.Settings.Backup
+	if cfg == nil {
+		return nil
+	}
+
+	backups, err := app.ListBackups()
+	if err != nil {
+		return err
+	}
+
+	cutoff := time.Time{}
+	if cfg.MaxAgeDays > 0 {
+		cutoff = time.Now().AddDate(0, 0, -cfg.MaxAgeDays)
+	}
+
+	for i, backup := range backups {
+		tooMany := cfg.Keep > 0 && i >= cfg.Keep
+		tooOld := !cutoff.IsZero() && backup.CreatedAt.Before(cutoff)
+		if tooMany || tooOld {
+			if err := system.RemoveDirectory(filepath.Join(app.backupRoot(), backup.ID)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (app *App) loadBackup(id string) (*Backup, error) {
+	data, err := os.ReadFile(filepath.Join(app.backupRoot(), id, backupManifestName))
+	if err != nil {
+		return nil, err
+	}
+
+	var backup Backup
+	if err := toml.Unmarshal(data, &backup); err != nil {
+		return nil, err
+	}
+	return &backup, nil
+}
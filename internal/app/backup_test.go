@@ -0,0 +1,96 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bnema/gart/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApp_DeployDotfile_BacksUpOverwrittenFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	storeDir := filepath.Join(tempDir, "store")
+	localDir := filepath.Join(tempDir, "home", ".config", "fish")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(storeDir, "fish"), 0755))
+	require.NoError(t, os.MkdirAll(localDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(storeDir, "fish", "config.fish"), []byte("set -x EDITOR nvim"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(localDir, "config.fish"), []byte("set -x EDITOR vim # local edit"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(localDir, "local_only.fish"), []byte("keep me"), 0644))
+
+	app := &App{
+		StoragePath: storeDir,
+		Config: &config.Config{
+			Settings: config.SettingsConfig{
+				Backup: &config.BackupConfig{
+					Enabled: true,
+					Path:    filepath.Join(tempDir, "backups"),
+					Keep:    5,
+				},
+			},
+			Dotfiles: map[string]string{"fish": localDir},
+		},
+	}
+
+	result := app.DeployDotfile("fish")
+	require.NoError(t, result.Err)
+	require.NotNil(t, result.Backup)
+	assert.Equal(t, []string{filepath.Join(localDir, "config.fish")}, result.Backup.Paths)
+
+	content, err := os.ReadFile(filepath.Join(localDir, "config.fish"))
+	require.NoError(t, err)
+	assert.Equal(t, "set -x EDITOR nvim", string(content))
+
+	// Files unknown to the store are left alone
+	_, err = os.Stat(filepath.Join(localDir, "local_only.fish"))
+	assert.NoError(t, err)
+
+	backups, err := app.ListBackups()
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	assert.Equal(t, "fish", backups[0].Dotfile)
+	assert.Equal(t, "deploy", backups[0].Reason)
+
+	// Restoring brings the local edit back
+	_, err = app.RestoreBackup(backups[0].ID)
+	require.NoError(t, err)
+	content, err = os.ReadFile(filepath.Join(localDir, "config.fish"))
+	require.NoError(t, err)
+	assert.Equal(t, "set -x EDITOR vim # local edit", string(content))
+}
+
+func TestApp_PruneBackups(t *testing.T) {
+	tempDir := t.TempDir()
+	localFile := filepath.Join(tempDir, "gitconfig")
+	require.NoError(t, os.WriteFile(localFile, []byte("[user]"), 0644))
+
+	app := &App{
+		StoragePath: filepath.Join(tempDir, "store"),
+		Config: &config.Config{
+			Settings: config.SettingsConfig{
+				Backup: &config.BackupConfig{
+					Enabled: true,
+					Path:    filepath.Join(tempDir, "backups"),
+					Keep:    2,
+				},
+			},
+		},
+	}
+
+	for i := 0; i < 4; i++ {
+		_, err := app.CreateBackup("git", "test", []string{localFile})
+		require.NoError(t, err)
+		time.Sleep(2 * time.Millisecond) // Backup IDs have millisecond precision
+	}
+
+	backups, err := app.ListBackups()
+	require.NoError(t, err)
+	assert.Len(t, backups, 2)
+
+	_, err = app.RestoreBackup("../etc")
+	assert.Error(t, err)
+}
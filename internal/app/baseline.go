@@ -0,0 +1,56 @@
+package app
+
+import (
+	"path/filepath"
+
+	"github.com/bnema/gart/internal/security"
+)
+
+// BaselinePath returns the file of reviewed security findings, set with the baseline
+// security setting or next to the config file
+func (app *App) BaselinePath() string {
+	if settings := app.Config.Settings.Security; settings != nil && settings.Baseline != "" {
+		return app.ExpandHomeDir(settings.Baseline)
+	}
+	return filepath.Join(filepath.Dir(app.ConfigFilePath), "security-baseline.toml")
+}
+
+// Baseline returns the reviewed security findings, loading them on first use
+func (app *App) Baseline() (*security.Baseline, error) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	if app.baseline == nil {
+		baseline, err := security.LoadBaseline(app.BaselinePath())
+		if err != nil {
+			return nil, err
+		}
+		app.baseline = baseline
+	}
+	return app.baseline, nil
+}
+
+// NewSecurityContext returns a security context for config whose scans leave out the
+// findings accepted in the baseline
+func (app *App) NewSecurityContext(config *security.SecurityConfig) (*security.SecurityContext, error) {
+	baseline, err := app.Baseline()
+	if err != nil {
+		return nil, err
+	}
+
+	securityContext := security.NewSecurityContext(config)
+	securityContext.SetBaseline(baseline)
+	return securityContext, nil
+}
+
+// UpdateBaseline accepts the findings of the report, replacing the accepted findings of
+// the scanned files. It returns the number of findings accepted for these files.
+func (app *App) UpdateBaseline(report *security.ScanReport) (int, error) {
+	baseline, err := app.Baseline()
+	if err != nil {
+		return 0, err
+	}
+
+	accepted := baseline.Update(report)
+	return accepted, baseline.Save()
+}
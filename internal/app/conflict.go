@@ -0,0 +1,121 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bnema/gart/internal/system"
+)
+
+// Resolutions of a dotfile that changed both locally and in the store since its last sync
+const (
+	// ResolveLocal keeps the local version and overwrites the store
+	ResolveLocal = "local"
+	// ResolveStore keeps the store version and overwrites the local files
+	ResolveStore = "store"
+	// ResolveMerge opens the conflicting files with conflict markers in $EDITOR
+	ResolveMerge = "merge"
+	// ResolveMarkers writes conflict markers into the local files, backing up their local versions
+	ResolveMarkers = "markers"
+)
+
+// ValidateResolution checks that the given conflict resolution is supported
+func ValidateResolution(resolution string) error {
+	switch resolution {
+	case "", ResolveLocal, ResolveStore, ResolveMerge, ResolveMarkers:
+		return nil
+	default:
+		return fmt.Errorf("invalid resolution '%s': must be one of: %s, %s, %s, %s",
+			resolution, ResolveLocal, ResolveStore, ResolveMerge, ResolveMarkers)
+	}
+}
+
+// DetectConflicts returns the files of a dotfile that changed both locally and in the
+// store since its last sync. Dotfiles never synced before have no conflicts.
+func (app *App) DetectConflicts(name, localPath, storePath string) ([]system.Conflict, error) {
+	if app.IsSymlinkMode(name) {
+		return nil, nil
+	}
+
+	manifest, err := app.Manifest()
+	if err != nil {
+		return nil, err
+	}
+	last, ok := manifest.LastSync(name)
+	if !ok {
+		return nil, nil
+	}
+
+	return system.FindConflicts(localPath, storePath, last, app.Config.DotfilesIgnores[name], manifest)
+}
+
+// WriteConflictMarkers replaces each conflicting local file with conflict markers between
+// its local and store versions. The local versions are saved in a backup first, which is
+// returned, rather than next to the files where they would be synced.
+func (app *App) WriteConflictMarkers(name, localPath, storePath string, conflicts []system.Conflict) (*Backup, error) {
+	// Templated and encrypted dotfiles are merged with their rendered store content
+	_, storeSide, cleanup, err := app.SyncSides(name, localPath, storePath, true)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	var locals []string
+	for _, conflict := range conflicts {
+		locals = append(locals, conflict.Local)
+	}
+	backup, err := app.CreateBackup(name, "conflict", locals)
+	if err != nil {
+		return nil, fmt.Errorf("failed to back up the local versions: %w", err)
+	}
+
+	for _, conflict := range conflicts {
+		storeContent, err := os.ReadFile(filepath.Join(storeSide, filepath.FromSlash(conflict.Path)))
+		if err != nil && !os.IsNotExist(err) {
+			return backup, fmt.Errorf("failed to read store version of %s: %w", conflict.Path, err)
+		}
+		if err := system.WriteConflictFile(conflict.Local, storeContent); err != nil {
+			return backup, fmt.Errorf("failed to write conflict markers into %s: %w", conflict.Local, err)
+		}
+	}
+	return backup, nil
+}
+
+// MergeConflicts writes conflict markers into the conflicting local files and opens
+// each of them in the editor. It fails if markers are left after the editor exits.
+func (app *App) MergeConflicts(name, localPath, storePath string, conflicts []system.Conflict) error {
+	backup, err := app.WriteConflictMarkers(name, localPath, storePath, conflicts)
+	if err != nil {
+		return err
+	}
+
+	for _, conflict := range conflicts {
+		if err := system.OpenInEditor(conflict.Local); err != nil {
+			return fmt.Errorf("failed to run editor: %w", err)
+		}
+		if err := CheckMarkersResolved([]system.Conflict{conflict}); err != nil {
+			if backup != nil {
+				return fmt.Errorf("%w, the local versions are in backup '%s'", err, backup.ID)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckMarkersResolved fails when a conflicting local file still contains conflict markers
+func CheckMarkersResolved(conflicts []system.Conflict) error {
+	for _, conflict := range conflicts {
+		content, err := os.ReadFile(conflict.Local)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if system.HasConflictMarkers(content) {
+			return fmt.Errorf("unresolved conflict markers left in %s", conflict.Local)
+		}
+	}
+	return nil
+}
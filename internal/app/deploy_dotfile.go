@@ -0,0 +1,132 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/bnema/gart/internal/config"
+	"github.com/bnema/gart/internal/system"
+)
+
+// DeployResult holds the outcome of deploying a single dotfile
+type DeployResult struct {
+	Name      string
+	StorePath string
+	LocalPath string
+	Backup    *Backup
+	Err       error
+}
+
+// StorePathFor returns the location of a dotfile inside the store.
+// Directories are stored under the dotfile name, files under the dotfile name
+// with their extension (as done by sync) or under their original base name (as done by add).
+// A path overridden by a profile keeps the store entry of the path it overrides.
+func (app *App) StorePathFor(name, path string) string {
+	stored := path
+	if app.Config != nil {
+		if base, ok := app.Config.BasePath(name); ok {
+			stored = app.ExpandHomeDir(base)
+		}
+	}
+
+	candidates := []string{
+		filepath.Join(app.StoragePath, name),
+		filepath.Join(app.StoragePath, name+filepath.Ext(stored)),
+		filepath.Join(app.StoragePath, filepath.Base(stored)),
+	}
+
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+
+	if app.IsDir(path) {
+		return candidates[0]
+	}
+	return candidates[1]
+}
+
+// DeployDotfile copies a stored dotfile back to its recorded local path
+func (app *App) DeployDotfile(name string) DeployResult {
+	path, ok := app.Config.Dotfiles[name]
+	if !ok {
+		return DeployResult{Name: name, Err: fmt.Errorf("dotfile '%s' not found", name)}
+	}
+
+	path = app.ExpandHomeDir(path)
+	result := DeployResult{
+		Name:      name,
+		StorePath: app.StorePathFor(name, path),
+		LocalPath: path,
+	}
+
+	if _, err := os.Stat(result.StorePath); err != nil {
+		result.Err = fmt.Errorf("store entry not found: %w", err)
+		return result
+	}
+
+	if app.IsSymlinkMode(name) {
+		result.Err = app.LinkDotfile(name)
+		return result
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		result.Err = fmt.Errorf("failed to create parent directory: %w", err)
+		return result
+	}
+
+	_, storeSide, cleanup, err := app.SyncSides(name, path, result.StorePath, true)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	defer cleanup()
+
+	ignores := app.Config.DotfilesIgnores[name]
+	plan, err := system.PlanChanges(storeSide, path, ignores)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to compare %s with %s: %w", result.StorePath, path, err)
+		return result
+	}
+
+	// Deploy only adds and updates files, local files unknown to the store are kept
+	plan = plan.Without(system.ChangeDelete)
+
+	if result.Backup, err = app.BackupPlanTargets(name, "deploy", plan); err != nil {
+		result.Err = fmt.Errorf("failed to back up local files: %w", err)
+		return result
+	}
+
+	if err := system.ApplyPlan(plan, ignores); err != nil {
+		result.Err = fmt.Errorf("failed to copy %s to %s: %w", result.StorePath, path, err)
+		return result
+	}
+
+	if result.Err = app.RecordSync(name, path, result.StorePath); result.Err != nil || plan.IsEmpty() {
+		return result
+	}
+
+	hc := app.hookContext(name, DirectionPull)
+	hc.ChangedFiles = plan.Targets(path, ignores)
+	result.Err = app.RunHooks(config.HookPostDeploy, hc)
+	return result
+}
+
+// DeployDotfiles deploys the named dotfiles, or every configured dotfile when no name is given
+func (app *App) DeployDotfiles(names []string) []DeployResult {
+	if len(names) == 0 {
+		for name := range app.Config.Dotfiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	results := make([]DeployResult, 0, len(names))
+	for _, name := range names {
+		results = append(results, app.DeployDotfile(name))
+	}
+	return results
+}
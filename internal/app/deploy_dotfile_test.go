@@ -0,0 +1,71 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bnema/gart/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApp_DeployDotfiles(t *testing.T) {
+	tempDir := t.TempDir()
+	storeDir := filepath.Join(tempDir, "store")
+	homeDir := filepath.Join(tempDir, "home")
+
+	// Store contains a directory entry and a single file entry
+	require.NoError(t, os.MkdirAll(filepath.Join(storeDir, "nvim", "lua"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(storeDir, "nvim", "init.lua"), []byte("-- init"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(storeDir, "nvim", "lua", "plugins.lua"), []byte("-- plugins"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(storeDir, "nvim", "session.log"), []byte("log"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(storeDir, "starship.toml"), []byte("format = '$all'"), 0644))
+
+	app := &App{
+		StoragePath: storeDir,
+		Config: &config.Config{
+			Dotfiles: map[string]string{
+				"nvim":     filepath.Join(homeDir, ".config", "nvim"),
+				"starship": filepath.Join(homeDir, ".config", "starship.toml"),
+				"missing":  filepath.Join(homeDir, ".missingrc"),
+			},
+			DotfilesIgnores: map[string][]string{
+				"nvim": {"*.log"},
+			},
+		},
+	}
+
+	results := app.DeployDotfiles(nil)
+	require.Len(t, results, 3)
+
+	// Results are sorted by name
+	assert.Equal(t, "missing", results[0].Name)
+	assert.Error(t, results[0].Err)
+	assert.Equal(t, "nvim", results[1].Name)
+	assert.NoError(t, results[1].Err)
+	assert.Equal(t, "starship", results[2].Name)
+	assert.NoError(t, results[2].Err)
+
+	content, err := os.ReadFile(filepath.Join(homeDir, ".config", "nvim", "lua", "plugins.lua"))
+	require.NoError(t, err)
+	assert.Equal(t, "-- plugins", string(content))
+
+	content, err = os.ReadFile(filepath.Join(homeDir, ".config", "starship.toml"))
+	require.NoError(t, err)
+	assert.Equal(t, "format = '$all'", string(content))
+
+	_, err = os.Stat(filepath.Join(homeDir, ".config", "nvim", "session.log"))
+	assert.True(t, os.IsNotExist(err), "Ignored file should not be deployed")
+}
+
+func TestApp_DeployDotfile_UnknownName(t *testing.T) {
+	app := &App{
+		StoragePath: t.TempDir(),
+		Config:      &config.Config{Dotfiles: map[string]string{}},
+	}
+
+	result := app.DeployDotfile("nope")
+	assert.Error(t, result.Err)
+	assert.Contains(t, result.Err.Error(), "not found")
+}
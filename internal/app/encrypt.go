@@ -0,0 +1,182 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bnema/gart/internal/config"
+	"github.com/bnema/gart/internal/system"
+)
+
+// PassphraseEnv is the environment variable holding the encryption passphrase
+const PassphraseEnv = "GART_PASSPHRASE"
+
+// IsEncrypted reports whether the store content of a dotfile is encrypted with age
+func (app *App) IsEncrypted(name string) bool {
+	return app.Config.DotfilesEncrypt[name]
+}
+
+// SetDotfileEncrypted enables or disables encrypted storage for a dotfile
+func (app *App) SetDotfileEncrypted(name string, enabled bool) error {
+	if app.Config.DotfilesEncrypt == nil {
+		app.Config.DotfilesEncrypt = make(map[string]bool)
+	}
+	if enabled {
+		app.Config.DotfilesEncrypt[name] = true
+	} else {
+		delete(app.Config.DotfilesEncrypt, name)
+	}
+
+	return config.UpdateDotfileEncrypt(app.ConfigFilePath, name, enabled)
+}
+
+// Encrypter returns the age encrypter configured in the settings, asking for
+// the passphrase once if passphrase encryption is used
+func (app *App) Encrypter() (*system.Encrypter, error) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	if app.encrypter != nil {
+		return app.encrypter, nil
+	}
+
+	settings := app.Config.Settings.Encryption
+	if settings == nil {
+		settings = &config.EncryptionConfig{}
+	}
+
+	var enc *system.Encrypter
+	var err error
+	if settings.Passphrase {
+		passphrase := os.Getenv(PassphraseEnv)
+		if passphrase == "" && app.NonInteractive() {
+			return nil, fmt.Errorf("set %s to provide the passphrase: %w", PassphraseEnv, system.ErrInputRequired)
+		}
+		if passphrase == "" {
+			if passphrase, err = system.PromptForPassphrase("Encryption passphrase: "); err != nil {
+				return nil, fmt.Errorf("failed to read passphrase: %w", err)
+			}
+		}
+		enc, err = system.NewPassphraseEncrypter(passphrase)
+	} else {
+		identity := settings.Identity
+		if identity == "" {
+			identity = config.DefaultIdentityPath()
+		}
+		enc, err = system.NewX25519Encrypter(app.ExpandHomeDir(identity), settings.Recipients)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	app.encrypter = enc
+	return enc, nil
+}
+
+// EncryptDotfile marks a dotfile for encrypted storage and encrypts its store entry in place
+func (app *App) EncryptDotfile(name string) error {
+	path, ok := app.Config.Dotfiles[name]
+	if !ok {
+		return fmt.Errorf("dotfile '%s' not found", name)
+	}
+	storePath := app.StorePathFor(name, app.ExpandHomeDir(path))
+
+	enc, err := app.Encrypter()
+	if err != nil {
+		return err
+	}
+
+	stageDir, err := os.MkdirTemp("", "gart-encrypt-*")
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(stageDir) }()
+
+	staged := filepath.Join(stageDir, filepath.Base(storePath))
+	if err := system.EncryptTree(storePath, storePath, staged, enc); err != nil {
+		return fmt.Errorf("failed to encrypt '%s': %w", name, err)
+	}
+
+	plan, err := system.PlanChanges(staged, storePath, nil)
+	if err != nil {
+		return err
+	}
+	if err := system.ApplyPlan(plan, nil); err != nil {
+		return fmt.Errorf("failed to write encrypted '%s': %w", name, err)
+	}
+
+	return app.SetDotfileEncrypted(name, true)
+}
+
+// AddDotfileEncrypted adds a dotfile with an encrypted store entry. The content is encrypted
+// in a staging directory so the plaintext never reaches the store, and the dotfile is only
+// registered once its ciphertext is in place. Nothing is left behind when it fails.
+func (app *App) AddDotfileEncrypted(path, name string, ignores []string) (err error) {
+	path = filepath.Clean(app.ExpandHomeDir(path))
+	if _, ok := app.Config.Dotfiles[name]; ok {
+		return fmt.Errorf("dotfile '%s' already exists", name)
+	}
+
+	storePath := filepath.Join(app.StoragePath, name)
+	if !app.IsDir(path) {
+		storePath = filepath.Join(app.StoragePath, filepath.Base(path))
+	}
+	if _, err := os.Lstat(storePath); err == nil {
+		return fmt.Errorf("store entry %s already exists", storePath)
+	}
+
+	enc, err := app.Encrypter()
+	if err != nil {
+		return err
+	}
+
+	stageDir, err := os.MkdirTemp("", "gart-encrypt-*")
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(stageDir) }()
+
+	plain := filepath.Join(stageDir, "plain")
+	if err := system.CopyPath(path, plain, ignores); err != nil {
+		return fmt.Errorf("error copying '%s': %w", name, err)
+	}
+	staged := filepath.Join(stageDir, "encrypted")
+	if err := system.EncryptTree(plain, storePath, staged, enc); err != nil {
+		return fmt.Errorf("failed to encrypt '%s': %w", name, err)
+	}
+
+	defer func() {
+		if err != nil {
+			if discardErr := app.DiscardDotfile(name, path); discardErr != nil {
+				err = fmt.Errorf("%w (cleanup failed: %v)", err, discardErr)
+			}
+		}
+	}()
+
+	if err := system.MovePath(staged, storePath); err != nil {
+		return fmt.Errorf("failed to write encrypted '%s': %w", name, err)
+	}
+	return app.updateConfig(name, path, ignores, true)
+}
+
+// DiscardDotfile undoes a dotfile add that failed: it removes the store entry of the dotfile at
+// path and its config entry, without committing
+func (app *App) DiscardDotfile(name, path string) error {
+	if err := os.RemoveAll(app.StorePathFor(name, path)); err != nil {
+		return fmt.Errorf("error removing store entry: %w", err)
+	}
+
+	delete(app.Config.Dotfiles, name)
+	delete(app.Config.DotfilesIgnores, name)
+	delete(app.Config.DotfilesModes, name)
+	delete(app.Config.DotfilesTemplates, name)
+	delete(app.Config.DotfilesEncrypt, name)
+	if _, err := os.Stat(app.ConfigFilePath); err == nil {
+		if err := config.RemoveDotfileFromConfig(app.ConfigFilePath, name); err != nil {
+			return err
+		}
+	}
+
+	return app.ForgetSync(name)
+}
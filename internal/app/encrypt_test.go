@@ -0,0 +1,135 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bnema/gart/internal/config"
+	"github.com/bnema/gart/internal/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApp_EncryptedDotfile(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.toml")
+	storeDir := filepath.Join(tempDir, "store")
+	localFile := filepath.Join(tempDir, "home", ".netrc")
+	storeFile := filepath.Join(storeDir, ".netrc")
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(localFile), 0755))
+	require.NoError(t, os.WriteFile(localFile, []byte("machine example.com password hunter2\n"), 0600))
+
+	cfg := &config.Config{
+		Settings: config.SettingsConfig{
+			Backup:     &config.BackupConfig{Enabled: false},
+			Encryption: &config.EncryptionConfig{Identity: filepath.Join(tempDir, "age.key")},
+		},
+		Dotfiles: map[string]string{},
+	}
+	require.NoError(t, config.SaveConfig(configPath, cfg))
+
+	app := &App{ConfigFilePath: configPath, StoragePath: storeDir, Config: cfg}
+	require.NoError(t, app.AddDotfile(localFile, "netrc", nil))
+	require.NoError(t, app.EncryptDotfile("netrc"))
+
+	saved, err := config.LoadConfig(configPath)
+	require.NoError(t, err)
+	assert.True(t, saved.DotfilesEncrypt["netrc"])
+
+	stored, err := os.ReadFile(storeFile)
+	require.NoError(t, err)
+	assert.True(t, system.IsEncrypted(stored))
+	assert.NotContains(t, string(stored), "hunter2")
+
+	// An unchanged local file produces no store change on push
+	localSide, _, cleanup, err := app.SyncSides("netrc", localFile, storeFile, false)
+	require.NoError(t, err)
+	defer cleanup()
+	plan, err := system.PlanSync(localSide, storeFile, nil, false)
+	require.NoError(t, err)
+	assert.True(t, plan.IsEmpty(), "Unchanged content must keep its ciphertext")
+
+	// Deploy decrypts the store content
+	require.NoError(t, os.WriteFile(localFile, []byte("stale\n"), 0600))
+	result := app.DeployDotfile("netrc")
+	require.NoError(t, result.Err)
+	content, err := os.ReadFile(localFile)
+	require.NoError(t, err)
+	assert.Equal(t, "machine example.com password hunter2\n", string(content))
+}
+
+func TestApp_AddDotfileEncrypted(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.toml")
+	storeDir := filepath.Join(tempDir, "store")
+	localDir := filepath.Join(tempDir, "home", ".aws")
+
+	require.NoError(t, os.MkdirAll(localDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(localDir, "credentials"), []byte("secret = hunter2\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(localDir, "cli.log"), []byte("log\n"), 0600))
+
+	cfg := &config.Config{
+		Settings: config.SettingsConfig{
+			Backup:     &config.BackupConfig{Enabled: false},
+			Encryption: &config.EncryptionConfig{Identity: filepath.Join(tempDir, "age.key")},
+		},
+		Dotfiles: map[string]string{},
+	}
+	require.NoError(t, config.SaveConfig(configPath, cfg))
+	app := &App{ConfigFilePath: configPath, StoragePath: storeDir, Config: cfg}
+
+	require.NoError(t, app.AddDotfileEncrypted(localDir, "aws", []string{"*.log"}))
+
+	saved, err := config.LoadConfig(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, localDir, saved.Dotfiles["aws"])
+	assert.True(t, saved.DotfilesEncrypt["aws"])
+	assert.NoFileExists(t, filepath.Join(storeDir, "aws", "cli.log"))
+	stored, err := os.ReadFile(filepath.Join(storeDir, "aws", "credentials"))
+	require.NoError(t, err)
+	assert.True(t, system.IsEncrypted(stored))
+
+	// An existing dotfile is never overwritten
+	assert.Error(t, app.AddDotfileEncrypted(localDir, "aws", nil))
+	assert.FileExists(t, filepath.Join(storeDir, "aws", "credentials"))
+}
+
+func TestApp_AddDotfileEncrypted_Failure(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.toml")
+	storeDir := filepath.Join(tempDir, "store")
+	localFile := filepath.Join(tempDir, "home", ".netrc")
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(localFile), 0755))
+	require.NoError(t, os.WriteFile(localFile, []byte("machine example.com password hunter2\n"), 0600))
+
+	// The identity cannot be created below a regular file
+	blocker := filepath.Join(tempDir, "blocker")
+	require.NoError(t, os.WriteFile(blocker, nil, 0600))
+	cfg := &config.Config{
+		Settings: config.SettingsConfig{
+			Backup:     &config.BackupConfig{Enabled: false},
+			Encryption: &config.EncryptionConfig{Identity: filepath.Join(blocker, "age.key")},
+		},
+		Dotfiles: map[string]string{},
+	}
+	require.NoError(t, config.SaveConfig(configPath, cfg))
+
+	// Without an encrypter nothing is written to the store or the config
+	app := &App{ConfigFilePath: configPath, StoragePath: storeDir, Config: cfg}
+	assert.Error(t, app.AddDotfileEncrypted(localFile, "netrc", nil))
+	assert.NoFileExists(t, filepath.Join(storeDir, ".netrc"))
+	saved, err := config.LoadConfig(configPath)
+	require.NoError(t, err)
+	assert.NotContains(t, saved.Dotfiles, "netrc")
+
+	// A failure after the ciphertext is written removes the store entry again
+	cfg.Settings.Encryption = &config.EncryptionConfig{Identity: filepath.Join(tempDir, "age.key")}
+	app = &App{ConfigFilePath: filepath.Join(tempDir, "missing", "config.toml"), StoragePath: storeDir, Config: cfg}
+	assert.Error(t, app.AddDotfileEncrypted(localFile, "netrc", nil))
+	assert.NoFileExists(t, filepath.Join(storeDir, ".netrc"))
+	assert.NotContains(t, app.Config.Dotfiles, "netrc")
+	assert.False(t, app.IsEncrypted("netrc"))
+}
@@ -0,0 +1,115 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/bnema/gart/internal/git"
+	"github.com/bnema/gart/internal/system"
+)
+
+// historyRepository returns the git repository of the store for reading its history
+func (app *App) historyRepository() (git.GitRepository, error) {
+	if !app.Config.Settings.GitVersioning {
+		return nil, fmt.Errorf("git versioning is disabled, there is no history to read")
+	}
+	return app.getOrCreateGitRepository()
+}
+
+// storeRelPath returns the store entry of a dotfile relative to the store root,
+// or an empty path for the whole store when name is empty
+func (app *App) storeRelPath(name string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+
+	path, ok := app.Config.Dotfiles[name]
+	if !ok {
+		return "", fmt.Errorf("dotfile '%s' not found", name)
+	}
+
+	rel, err := filepath.Rel(app.StoragePath, app.StorePathFor(name, app.ExpandHomeDir(path)))
+	if err != nil {
+		return "", fmt.Errorf("failed to locate '%s' in the store: %w", name, err)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// DotfileLog returns the commits that touched the store entry of a dotfile, or any
+// file of the store when name is empty, newest first
+func (app *App) DotfileLog(name string, limit int) ([]git.CommitInfo, error) {
+	path, err := app.storeRelPath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := app.historyRepository()
+	if err != nil {
+		return nil, err
+	}
+	return repo.Log(path, limit)
+}
+
+// ShowRevision returns a commit and the changes it made to a dotfile, or to the whole
+// store when name is empty. Encrypted content is decrypted.
+func (app *App) ShowRevision(rev, name string) (*git.CommitInfo, []git.FileChange, error) {
+	path, err := app.storeRelPath(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	repo, err := app.historyRepository()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info, files, err := repo.Show(rev, path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := range files {
+		if files[i].From, err = app.decryptHistory(files[i].From); err != nil {
+			return nil, nil, err
+		}
+		if files[i].To, err = app.decryptHistory(files[i].To); err != nil {
+			return nil, nil, err
+		}
+	}
+	return info, files, nil
+}
+
+// decryptHistory decrypts content read from the store history if it is age encrypted
+func (app *App) decryptHistory(content []byte) ([]byte, error) {
+	if !system.IsEncrypted(content) {
+		return content, nil
+	}
+
+	enc, err := app.Encrypter()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load encryption key: %w", err)
+	}
+	plain, err := enc.Decrypt(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+	return plain, nil
+}
+
+// headCommit returns the hash of the last commit of the store, or an empty string
+// without versioning. name limits the changes read to those of a dotfile.
+func (app *App) headCommit(name string) string {
+	path, err := app.storeRelPath(name)
+	if err != nil {
+		return ""
+	}
+	repo, err := app.historyRepository()
+	if err != nil {
+		return ""
+	}
+	info, _, err := repo.Show("HEAD", path)
+	if err != nil {
+		return ""
+	}
+	return info.Hash
+}
@@ -0,0 +1,138 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bnema/gart/internal/config"
+	"github.com/bnema/gart/internal/system"
+)
+
+// defaultHookTimeout bounds the hooks without a configured timeout
+const defaultHookTimeout = 30 * time.Second
+
+// Sync directions exposed to hooks
+const (
+	// DirectionPush copies local files to the store
+	DirectionPush = "push"
+	// DirectionPull copies the store to local files
+	DirectionPull = "pull"
+)
+
+// HookContext describes the operation a hook runs for. It is exposed to the hook
+// as GART_* environment variables.
+type HookContext struct {
+	Dotfile   string
+	LocalPath string
+	StorePath string
+	Direction string
+	// Action is the commit action, only set for pre_commit
+	Action string
+	// ChangedFiles are the paths changed by the operation, relative to the dotfile
+	ChangedFiles []string
+}
+
+// hookContext returns the context of a hook for a configured dotfile
+func (app *App) hookContext(name, direction string) HookContext {
+	hc := HookContext{Dotfile: name, Direction: direction}
+	if path, ok := app.Config.Dotfiles[name]; ok {
+		hc.LocalPath = app.ExpandHomeDir(path)
+		hc.StorePath = app.StorePathFor(name, hc.LocalPath)
+	}
+	return hc
+}
+
+// SyncHookContext returns the context of the sync hooks of a dotfile
+func (app *App) SyncHookContext(name string, reverse bool) HookContext {
+	if reverse {
+		return app.hookContext(name, DirectionPull)
+	}
+	return app.hookContext(name, DirectionPush)
+}
+
+// RunPreAddHooks runs the pre_add hooks of a dotfile about to be added from path
+func (app *App) RunPreAddHooks(name, path string) error {
+	return app.RunHooks(config.HookPreAdd, HookContext{
+		Dotfile:   name,
+		LocalPath: path,
+		StorePath: app.StorePathFor(name, path),
+		Direction: DirectionPush,
+	})
+}
+
+// env returns the environment variables describing the context to a hook
+func (hc HookContext) env(event, storage string) []string {
+	return []string{
+		"GART_HOOK=" + event,
+		"GART_DOTFILE=" + hc.Dotfile,
+		"GART_LOCAL_PATH=" + hc.LocalPath,
+		"GART_STORE_PATH=" + hc.StorePath,
+		"GART_STORAGE_PATH=" + storage,
+		"GART_DIRECTION=" + hc.Direction,
+		"GART_ACTION=" + hc.Action,
+		"GART_CHANGED_FILES=" + strings.Join(hc.ChangedFiles, "\n"),
+	}
+}
+
+// RunHooks runs the global hooks of an event, then those of the dotfile. A failing hook
+// aborts with an error unless it is set to warn, which only prints a warning.
+func (app *App) RunHooks(event string, hc HookContext) error {
+	hooksConfig := app.Config.Settings.Hooks
+	if hooksConfig == nil {
+		return nil
+	}
+
+	hooks := hooksConfig.Event(event)
+	if dotfileHooks, ok := hooksConfig.Dotfiles[hc.Dotfile]; ok {
+		hooks = append(hooks[:len(hooks):len(hooks)], dotfileHooks.Event(event)...)
+	}
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	defaultTimeout := defaultHookTimeout
+	if hooksConfig.Timeout != "" {
+		var err error
+		if defaultTimeout, err = time.ParseDuration(hooksConfig.Timeout); err != nil {
+			return fmt.Errorf("invalid hooks timeout '%s': %w", hooksConfig.Timeout, err)
+		}
+	}
+
+	env := hc.env(event, app.StoragePath)
+	for _, hook := range hooks {
+		timeout := defaultTimeout
+		if hook.Timeout != "" {
+			var err error
+			if timeout, err = time.ParseDuration(hook.Timeout); err != nil {
+				return fmt.Errorf("invalid timeout '%s' of %s hook '%s': %w", hook.Timeout, event, hook.Run, err)
+			}
+		}
+
+		switch hook.OnFailure {
+		case "", config.HookAbort, config.HookWarn:
+		default:
+			return fmt.Errorf("invalid on_failure '%s' of %s hook '%s': must be %s or %s",
+				hook.OnFailure, event, hook.Run, config.HookAbort, config.HookWarn)
+		}
+
+		if err := system.RunHookCommand(hook.Run, env, timeout, app.hookStdout()); err != nil {
+			if hook.OnFailure == config.HookWarn {
+				fmt.Fprintf(os.Stderr, "Warning: %s hook '%s' failed: %v\n", event, hook.Run, err)
+				continue
+			}
+			return fmt.Errorf("%s hook '%s' failed: %w", event, hook.Run, err)
+		}
+	}
+	return nil
+}
+
+// hookStdout is where the hooks print, stderr when stdout is reserved for JSON output
+func (app *App) hookStdout() io.Writer {
+	if app.JSONOutput {
+		return os.Stderr
+	}
+	return os.Stdout
+}
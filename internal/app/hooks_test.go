@@ -0,0 +1,133 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/bnema/gart/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApp_RunHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are run with sh")
+	}
+
+	tempDir := t.TempDir()
+	logPath := filepath.Join(tempDir, "hooks.log")
+	logHook := func(text string) config.Hook {
+		return config.Hook{Run: "echo " + text + " >> " + logPath}
+	}
+
+	app := &App{
+		StoragePath: filepath.Join(tempDir, "store"),
+		Config: &config.Config{
+			Dotfiles: map[string]string{"nvim": filepath.Join(tempDir, "nvim")},
+			Settings: config.SettingsConfig{
+				Hooks: &config.HooksConfig{
+					HookSet: config.HookSet{
+						PostSync: []config.Hook{logHook("global-$GART_DOTFILE-$GART_DIRECTION")},
+					},
+					Dotfiles: map[string]config.HookSet{
+						"nvim": {
+							PostSync: []config.Hook{
+								{Run: "exit 3", OnFailure: config.HookWarn},
+								logHook(`"$GART_CHANGED_FILES"`),
+							},
+							PreSync: []config.Hook{{Run: "exit 1"}, logHook("unreachable")},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	hc := app.SyncHookContext("nvim", false)
+	hc.ChangedFiles = []string{"init.lua", "lua/plugins.lua"}
+	require.NoError(t, app.RunHooks(config.HookPostSync, hc))
+
+	// Other dotfiles only run the global hooks
+	require.NoError(t, app.RunHooks(config.HookPostSync, app.SyncHookContext("tmux", true)))
+
+	content, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Equal(t, "global-nvim-push\ninit.lua\nlua/plugins.lua\nglobal-tmux-pull\n", string(content))
+
+	// A failing hook aborts by default and stops the following hooks
+	err = app.RunHooks(config.HookPreSync, hc)
+	assert.Error(t, err)
+	content, err = os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "unreachable")
+
+	// Events without hooks do nothing
+	assert.NoError(t, app.RunHooks(config.HookPreAdd, hc))
+}
+
+func TestApp_RunHooks_Timeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are run with sh")
+	}
+
+	app := &App{
+		Config: &config.Config{
+			Settings: config.SettingsConfig{
+				Hooks: &config.HooksConfig{
+					Timeout: "100ms",
+					HookSet: config.HookSet{
+						PreCommit: []config.Hook{{Run: "sleep 5"}},
+					},
+				},
+			},
+		},
+	}
+
+	err := app.RunHooks(config.HookPreCommit, HookContext{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out")
+
+	app.Config.Settings.Hooks.PreCommit[0].OnFailure = "ignore"
+	assert.Error(t, app.RunHooks(config.HookPreCommit, HookContext{}))
+}
+
+func TestApp_DeployDotfile_PostDeployHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are run with sh")
+	}
+
+	tempDir := t.TempDir()
+	storeDir := filepath.Join(tempDir, "store")
+	localDir := filepath.Join(tempDir, "home", "fontconfig")
+	logPath := filepath.Join(tempDir, "hooks.log")
+	require.NoError(t, os.MkdirAll(filepath.Join(storeDir, "fontconfig"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(storeDir, "fontconfig", "fonts.conf"), []byte("<fontconfig/>"), 0644))
+
+	app := &App{
+		StoragePath: storeDir,
+		Config: &config.Config{
+			Dotfiles: map[string]string{"fontconfig": localDir},
+			Settings: config.SettingsConfig{
+				Backup: &config.BackupConfig{Enabled: false},
+				Hooks: &config.HooksConfig{
+					Dotfiles: map[string]config.HookSet{
+						"fontconfig": {
+							PostDeploy: []config.Hook{{Run: `echo "$GART_DIRECTION $GART_CHANGED_FILES" >> ` + logPath}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	require.NoError(t, app.DeployDotfile("fontconfig").Err)
+
+	// Deploying again changes nothing and does not run the hook
+	require.NoError(t, app.DeployDotfile("fontconfig").Err)
+
+	content, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Equal(t, "pull fonts.conf\n", string(content))
+}
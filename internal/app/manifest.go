@@ -0,0 +1,60 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/bnema/gart/internal/system"
+)
+
+// ManifestPath returns the location of the manifest, stored next to the store
+// rather than inside it so that host specific stat data is never committed
+func (app *App) ManifestPath() string {
+	store := filepath.Clean(app.StoragePath)
+	return filepath.Join(filepath.Dir(store), filepath.Base(store)+".manifest.toml")
+}
+
+// Manifest returns the manifest of the store, loading it on first use
+func (app *App) Manifest() (*system.Manifest, error) {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	if app.manifest == nil {
+		manifest, err := system.LoadManifest(app.ManifestPath())
+		if err != nil {
+			return nil, err
+		}
+		app.manifest = manifest
+	}
+	return app.manifest, nil
+}
+
+// RecordSync saves the current state of both sides of a dotfile as its last synced state
+func (app *App) RecordSync(name, localPath, storePath string) error {
+	return app.recordSyncExcluding(name, localPath, storePath, nil)
+}
+
+// recordSyncExcluding implements RecordSync, the paths in exclude that were not synced keep
+// their previously recorded state
+func (app *App) recordSyncExcluding(name, localPath, storePath string, exclude []string) error {
+	manifest, err := app.Manifest()
+	if err != nil {
+		return err
+	}
+
+	ignores := app.Config.DotfilesIgnores[name]
+	if err := manifest.RecordSyncExcluding(name, localPath, storePath, ignores, exclude); err != nil {
+		return fmt.Errorf("failed to record sync state of '%s': %w", name, err)
+	}
+	return manifest.Save()
+}
+
+// ForgetSync drops the recorded sync state of a removed dotfile
+func (app *App) ForgetSync(name string) error {
+	manifest, err := app.Manifest()
+	if err != nil {
+		return err
+	}
+	manifest.Forget(name)
+	return manifest.Save()
+}
@@ -0,0 +1,139 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bnema/gart/internal/git"
+)
+
+// HostDiff lists the files of a dotfile that differ on another host's branch.
+// From is the content on the current branch and To the content on the host branch.
+type HostDiff struct {
+	Name  string
+	Files []git.FileChange
+}
+
+// Adoption selects what to take from another host's branch for a dotfile.
+// An empty Files list adopts every differing file of the dotfile.
+type Adoption struct {
+	Name  string
+	Files []string
+}
+
+// HostBranch fetches the remote of the store and returns the reference of another host's branch
+func (app *App) HostBranch(host string) (string, error) {
+	repo, err := app.historyRepository()
+	if err != nil {
+		return "", err
+	}
+
+	if err := repo.Fetch(); err != nil && !git.IsNoRemoteError(err) {
+		return "", err
+	}
+	return repo.ResolveBranch(host)
+}
+
+// DiffHost returns the named dotfiles, or every configured dotfile when no name is given,
+// that differ between the current branch and ref. Encrypted content is decrypted.
+func (app *App) DiffHost(ref string, names []string) ([]HostDiff, error) {
+	repo, err := app.historyRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(names) == 0 {
+		for name := range app.Config.Dotfiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	var diffs []HostDiff
+	for _, name := range names {
+		path, err := app.storeRelPath(name)
+		if err != nil {
+			return nil, err
+		}
+
+		files, err := repo.Diff("HEAD", ref, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compare '%s': %w", name, err)
+		}
+		if len(files) == 0 {
+			continue
+		}
+
+		for i := range files {
+			if files[i].From, err = app.decryptHistory(files[i].From); err != nil {
+				return nil, err
+			}
+			if files[i].To, err = app.decryptHistory(files[i].To); err != nil {
+				return nil, err
+			}
+		}
+		diffs = append(diffs, HostDiff{Name: name, Files: files})
+	}
+	return diffs, nil
+}
+
+// AdoptFromHost copies the selected dotfiles or files of another host's branch into the store,
+// records them as a single commit and deploys the adopted dotfiles to their local paths
+func (app *App) AdoptFromHost(host, ref string, adoptions []Adoption) ([]DeployResult, error) {
+	if len(adoptions) == 0 {
+		return nil, nil
+	}
+
+	repo, err := app.historyRepository()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(adoptions))
+	for _, adoption := range adoptions {
+		files := adoption.Files
+		if len(files) == 0 {
+			path, err := app.storeRelPath(adoption.Name)
+			if err != nil {
+				return nil, err
+			}
+			changes, err := repo.Diff("HEAD", ref, path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compare '%s': %w", adoption.Name, err)
+			}
+			for _, change := range changes {
+				files = append(files, change.Path)
+			}
+		}
+
+		for _, file := range files {
+			if err := app.adoptFile(repo, ref, file); err != nil {
+				return nil, fmt.Errorf("failed to adopt %s from %s: %w", file, host, err)
+			}
+		}
+		names = append(names, adoption.Name)
+	}
+
+	if err := app.GitCommitChanges("Merge from "+host, strings.Join(names, ", ")); err != nil {
+		return nil, fmt.Errorf("failed to commit merge: %w", err)
+	}
+
+	return app.DeployDotfiles(names), nil
+}
+
+// adoptFile checks out a store file from ref, or removes it when ref does not have it
+func (app *App) adoptFile(repo git.GitRepository, ref, file string) error {
+	_, err := repo.Restore(ref, file)
+	if err == nil || !errors.Is(err, git.ErrPathNotFound) {
+		return err
+	}
+
+	if err := os.Remove(filepath.Join(app.StoragePath, filepath.FromSlash(file))); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
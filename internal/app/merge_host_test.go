@@ -0,0 +1,131 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bnema/gart/internal/config"
+	"github.com/bnema/gart/internal/git"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newHostStore creates a store on a host branch with the given files committed
+func newHostStore(t *testing.T, remote, host string, files map[string]string) (git.GitRepository, string) {
+	t.Helper()
+	dir := t.TempDir()
+
+	repo, err := git.NewRepository(dir)
+	require.NoError(t, err)
+	require.NoError(t, repo.Init(host))
+	require.NoError(t, repo.SetRemote("origin", remote))
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	require.NoError(t, repo.Add("."))
+	require.NoError(t, repo.Commit("Add dotfiles"))
+	return repo, dir
+}
+
+func TestApp_MergeFromHost(t *testing.T) {
+	tests := []struct {
+		name     string
+		adoption Adoption
+		want     map[string]string // store files, empty content means absent
+	}{
+		{
+			name:     "whole dotfile",
+			adoption: Adoption{Name: "nvim"},
+			want: map[string]string{
+				"nvim/init.lua":    "-- laptop\n",
+				"nvim/plugins.lua": "-- plugins\n",
+				"nvim/old.lua":     "",
+			},
+		},
+		{
+			name:     "selected files",
+			adoption: Adoption{Name: "nvim", Files: []string{"nvim/init.lua"}},
+			want: map[string]string{
+				"nvim/init.lua":    "-- laptop\n",
+				"nvim/plugins.lua": "",
+				"nvim/old.lua":     "-- old\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remote := t.TempDir()
+			_, err := gogit.PlainInit(remote, true)
+			require.NoError(t, err)
+
+			laptop, _ := newHostStore(t, remote, "laptop", map[string]string{
+				"nvim/init.lua":    "-- laptop\n",
+				"nvim/plugins.lua": "-- plugins\n",
+			})
+			require.NoError(t, laptop.Push())
+
+			repo, storeDir := newHostStore(t, remote, "desktop", map[string]string{
+				"nvim/init.lua": "-- desktop\n",
+				"nvim/old.lua":  "-- old\n",
+				"bashrc":        "export A=1\n",
+			})
+			localDir := filepath.Join(t.TempDir(), "nvim")
+
+			app := &App{
+				StoragePath: storeDir,
+				Config: &config.Config{
+					Dotfiles: map[string]string{
+						"nvim":   localDir,
+						"bashrc": filepath.Join(t.TempDir(), ".bashrc"),
+					},
+					Settings: config.SettingsConfig{
+						GitVersioning: true,
+						Git:           config.GitConfig{CommitMessageFormat: "{{ .Action }} {{ .Dotfile }}"},
+						Backup:        &config.BackupConfig{Enabled: false},
+					},
+				},
+				gitRepo: repo,
+			}
+
+			ref, err := app.HostBranch("laptop")
+			require.NoError(t, err)
+
+			diffs, err := app.DiffHost(ref, []string{"nvim"})
+			require.NoError(t, err)
+			require.Len(t, diffs, 1)
+			assert.Len(t, diffs[0].Files, 3)
+
+			results, err := app.AdoptFromHost("laptop", ref, []Adoption{tt.adoption})
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+			require.NoError(t, results[0].Err)
+
+			for name, content := range tt.want {
+				got, err := os.ReadFile(filepath.Join(storeDir, name))
+				if content == "" {
+					assert.True(t, os.IsNotExist(err), "%s should not be in the store", name)
+					continue
+				}
+				require.NoError(t, err)
+				assert.Equal(t, content, string(got))
+			}
+
+			deployed, err := os.ReadFile(filepath.Join(localDir, "init.lua"))
+			require.NoError(t, err)
+			assert.Equal(t, "-- laptop\n", string(deployed))
+
+			commits, err := repo.Log("nvim", 1)
+			require.NoError(t, err)
+			assert.Equal(t, "Merge from laptop nvim", commits[0].Subject())
+			status, err := repo.Status()
+			require.NoError(t, err)
+			assert.Empty(t, status)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"github.com/bnema/gart/internal/config"
+	"github.com/bnema/gart/internal/system"
+)
+
+// ActivateProfile restricts the managed dotfiles to a profile.
+// When name is empty, the profile is selected by matching the hostname against the profile hosts.
+// If no profile matches, every dotfile stays active.
+func (app *App) ActivateProfile(name string) error {
+	app.mu.Lock()
+	defer app.mu.Unlock()
+
+	if name == "" {
+		hostname, err := system.GetHostname()
+		if err != nil {
+			return nil // Without a hostname there is nothing to match against
+		}
+		name = app.Config.ProfileForHost(hostname)
+		if name == "" {
+			return nil
+		}
+	}
+
+	if err := app.Config.ApplyProfile(name); err != nil {
+		return err
+	}
+	app.ActiveProfile = name
+	return nil
+}
+
+// addDotfileToActiveProfile keeps a newly added dotfile active under the current profile
+func (app *App) addDotfileToActiveProfile(dotfileName string) error {
+	if app.ActiveProfile == "" {
+		return nil
+	}
+	return config.AddDotfileToProfile(app.ConfigFilePath, app.ActiveProfile, dotfileName)
+}
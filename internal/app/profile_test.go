@@ -0,0 +1,79 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bnema/gart/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newProfileConfig() *config.Config {
+	return &config.Config{
+		Dotfiles: map[string]string{
+			"nvim": "/home/user/.config/nvim",
+			"git":  "/home/user/.gitconfig",
+			"hypr": "/home/user/.config/hypr",
+			"tmux": "/home/user/.tmux.conf",
+		},
+		Profiles: map[string]config.Profile{
+			"server": {
+				Hosts:    []string{"srv-*"},
+				Dotfiles: []string{"nvim", "tmux"},
+			},
+			"work": {
+				Hosts:    []string{"work-laptop", "*-corp"},
+				Dotfiles: []string{"nvim", "git"},
+				Paths:    map[string]string{"git": "/home/user/.gitconfig-work"},
+			},
+		},
+	}
+}
+
+func TestConfig_ProfileForHost(t *testing.T) {
+	cfg := newProfileConfig()
+
+	assert.Equal(t, "work", cfg.ProfileForHost("work-laptop"))
+	assert.Equal(t, "work", cfg.ProfileForHost("build-corp"))
+	assert.Equal(t, "server", cfg.ProfileForHost("srv-01"))
+	assert.Equal(t, "", cfg.ProfileForHost("home-desktop"))
+}
+
+func TestApp_ActivateProfile(t *testing.T) {
+	app := &App{Config: newProfileConfig()}
+
+	err := app.ActivateProfile("work")
+	require.NoError(t, err)
+	assert.Equal(t, "work", app.ActiveProfile)
+	assert.Equal(t, map[string]string{
+		"nvim": "/home/user/.config/nvim",
+		"git":  "/home/user/.gitconfig-work",
+	}, app.GetDotfiles())
+
+	app = &App{Config: newProfileConfig()}
+	err = app.ActivateProfile("missing")
+	assert.Error(t, err)
+	assert.Len(t, app.GetDotfiles(), 4, "Dotfiles must stay untouched for an unknown profile")
+}
+
+func TestApp_AddDotfile_KeepsProfileSelection(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.toml")
+	storeDir := filepath.Join(tempDir, "store")
+	localFile := filepath.Join(tempDir, "starship.toml")
+	require.NoError(t, os.WriteFile(localFile, []byte("format = '$all'"), 0644))
+
+	cfg := newProfileConfig()
+	require.NoError(t, config.SaveConfig(configPath, cfg))
+
+	app := &App{ConfigFilePath: configPath, StoragePath: storeDir, Config: cfg}
+	require.NoError(t, app.ActivateProfile("server"))
+	require.NoError(t, app.AddDotfile(localFile, "starship", nil))
+
+	saved, err := config.LoadConfig(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"nvim", "tmux", "starship"}, saved.Profiles["server"].Dotfiles)
+	assert.Len(t, saved.Dotfiles, 5, "The filtered view must not be written back to the config")
+}
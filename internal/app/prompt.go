@@ -0,0 +1,48 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/bnema/gart/internal/system"
+)
+
+// ConfigurePrompts switches to non-interactive mode when nonInteractive is set or the
+// non_interactive setting is enabled. A policy overrides the prompt_policy setting and
+// implies non-interactive mode.
+func (app *App) ConfigurePrompts(nonInteractive bool, policy string) error {
+	settings := app.Config.Settings
+	if !nonInteractive && policy == "" && !settings.NonInteractive {
+		app.PromptPolicy = ""
+		return nil
+	}
+
+	if policy == "" {
+		policy = settings.PromptPolicy
+	}
+	parsed, err := system.ParsePromptPolicy(policy)
+	if err != nil {
+		return err
+	}
+	app.PromptPolicy = parsed
+	return nil
+}
+
+// NonInteractive reports whether prompts are answered by the prompt policy
+func (app *App) NonInteractive() bool {
+	return app.PromptPolicy != ""
+}
+
+// Confirm asks a yes/no question, or answers it with the prompt policy in non-interactive
+// mode: proceed is a yes, skip is a no and fail returns system.ErrInputRequired
+func (app *App) Confirm(question string) (bool, error) {
+	switch app.PromptPolicy {
+	case "":
+		return system.PromptForConfirmation(question)
+	case system.PromptProceed:
+		return true, nil
+	case system.PromptSkip:
+		return false, nil
+	default:
+		return false, fmt.Errorf("%w: %s", system.ErrInputRequired, question)
+	}
+}
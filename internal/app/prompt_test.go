@@ -0,0 +1,46 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/bnema/gart/internal/config"
+	"github.com/bnema/gart/internal/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApp_ConfigurePrompts(t *testing.T) {
+	app := &App{Config: &config.Config{}}
+
+	require.NoError(t, app.ConfigurePrompts(false, ""))
+	assert.False(t, app.NonInteractive())
+
+	require.NoError(t, app.ConfigurePrompts(true, ""))
+	assert.Equal(t, system.PromptFail, app.PromptPolicy)
+
+	// The setting enables the mode, a policy flag overrides the configured policy
+	app.Config.Settings.NonInteractive = true
+	app.Config.Settings.PromptPolicy = "skip"
+	require.NoError(t, app.ConfigurePrompts(false, ""))
+	assert.Equal(t, system.PromptSkip, app.PromptPolicy)
+	require.NoError(t, app.ConfigurePrompts(false, "proceed"))
+	assert.Equal(t, system.PromptProceed, app.PromptPolicy)
+
+	assert.Error(t, app.ConfigurePrompts(false, "maybe"))
+}
+
+func TestApp_Confirm(t *testing.T) {
+	app := &App{PromptPolicy: system.PromptProceed}
+	confirmed, err := app.Confirm("Remove?")
+	require.NoError(t, err)
+	assert.True(t, confirmed)
+
+	app.PromptPolicy = system.PromptSkip
+	confirmed, err = app.Confirm("Remove?")
+	require.NoError(t, err)
+	assert.False(t, confirmed)
+
+	app.PromptPolicy = system.PromptFail
+	_, err = app.Confirm("Remove?")
+	assert.ErrorIs(t, err, system.ErrInputRequired)
+}
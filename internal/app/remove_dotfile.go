@@ -66,6 +66,20 @@ func (app *App) RemoveDotFile(path string, name string) error {
 	// Store the name of the dotfile to be removed
 	removedDotfileName := keyToRemove
 
+	// Symlinked dotfiles point into the store, so put a real copy back before deleting it
+	if app.Config != nil && app.IsSymlinkMode(keyToRemove) {
+		if err := app.UnlinkDotfile(keyToRemove); err != nil {
+			return fmt.Errorf("error restoring symlinked dotfile '%s': %w", keyToRemove, err)
+		}
+		// UnlinkDotfile rewrote the config file, reload the tree
+		if tree, err = toml.LoadFile(configFilePath); err != nil {
+			return fmt.Errorf("error loading config file: %w", err)
+		}
+		if dotfilesTree, ok = tree.Get("dotfiles").(*toml.Tree); !ok {
+			return fmt.Errorf("dotfiles section not found in config")
+		}
+	}
+
 	if err := dotfilesTree.Delete(keyToRemove); err != nil {
 		return fmt.Errorf("error removing dotfile key '%s' from config: %w", keyToRemove, err)
 	}
@@ -97,6 +111,10 @@ func (app *App) RemoveDotFile(path string, name string) error {
 		return fmt.Errorf("error removing dotfile from storage: %w", err)
 	}
 
+	if err := app.ForgetSync(removedDotfileName); err != nil {
+		return fmt.Errorf("error updating manifest: %w", err)
+	}
+
 	// Commit the changes
 	if err := app.GitCommitChanges("Remove", removedDotfileName); err != nil {
 		return fmt.Errorf("error committing changes for removing %s: %w", removedDotfileName, err)
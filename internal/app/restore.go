@@ -0,0 +1,78 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bnema/gart/internal/git"
+)
+
+// restoreTimeLayouts are the accepted layouts of a restore timestamp, in local time
+var restoreTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// ParseRestoreTime parses a restore timestamp such as 2026-09-01, 2026-09-01 18:30 or an RFC 3339 time
+func ParseRestoreTime(value string) (time.Time, error) {
+	for _, layout := range restoreTimeLayouts {
+		if at, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return at, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time '%s': use YYYY-MM-DD, 'YYYY-MM-DD HH:MM' or RFC 3339", value)
+}
+
+// RestoreResult describes a dotfile rolled back to an earlier version
+type RestoreResult struct {
+	Name string
+	// Commit is the revision the store entry was restored from
+	Commit *git.CommitInfo
+	// Deploy is the result of deploying the restored version, nil when it was not deployed
+	Deploy *DeployResult
+}
+
+// RestoreDotfile rolls the store entry of a dotfile back to a revision, or to the last commit
+// made at or before at when rev is empty, and records the rollback as a new commit.
+// With deploy, the restored version is also deployed to the local path.
+func (app *App) RestoreDotfile(name, rev string, at time.Time, deploy bool) (RestoreResult, error) {
+	result := RestoreResult{Name: name}
+
+	path, err := app.storeRelPath(name)
+	if err != nil {
+		return result, err
+	}
+	if path == "" {
+		return result, fmt.Errorf("a dotfile name is required")
+	}
+
+	repo, err := app.historyRepository()
+	if err != nil {
+		return result, err
+	}
+
+	if rev == "" {
+		if at.IsZero() {
+			return result, fmt.Errorf("a revision or a time is required")
+		}
+		if rev, err = repo.RevisionAt(at); err != nil {
+			return result, err
+		}
+	}
+
+	if result.Commit, err = repo.Restore(rev, path); err != nil {
+		return result, fmt.Errorf("failed to restore '%s': %w", name, err)
+	}
+
+	if err := app.GitCommitChanges("Rollback", name); err != nil {
+		return result, fmt.Errorf("failed to commit rollback: %w", err)
+	}
+
+	if deploy {
+		deployed := app.DeployDotfile(name)
+		result.Deploy = &deployed
+	}
+	return result, nil
+}
@@ -0,0 +1,78 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bnema/gart/internal/config"
+	"github.com/bnema/gart/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApp_RestoreDotfile(t *testing.T) {
+	tempDir := t.TempDir()
+	storeDir := filepath.Join(tempDir, "store")
+	localDir := filepath.Join(tempDir, "home", ".config", "nvim")
+	require.NoError(t, os.MkdirAll(filepath.Join(storeDir, "nvim"), 0755))
+
+	repo, err := git.NewRepository(storeDir)
+	require.NoError(t, err)
+	require.NoError(t, repo.Init("main"))
+
+	app := &App{
+		StoragePath: storeDir,
+		Config: &config.Config{
+			Dotfiles: map[string]string{"nvim": localDir},
+			Settings: config.SettingsConfig{
+				GitVersioning: true,
+				Git:           config.GitConfig{CommitMessageFormat: "{{ .Action }} {{ .Dotfile }}"},
+				Backup:        &config.BackupConfig{Enabled: false},
+			},
+		},
+		gitRepo: repo,
+	}
+
+	for _, version := range []string{"-- good\n", "-- broken\n"} {
+		require.NoError(t, os.WriteFile(filepath.Join(storeDir, "nvim", "init.lua"), []byte(version), 0644))
+		require.NoError(t, app.GitCommitChanges("Update", "nvim"))
+	}
+
+	result, err := app.RestoreDotfile("nvim", "HEAD~1", time.Time{}, true)
+	require.NoError(t, err)
+	assert.Equal(t, "Update nvim", result.Commit.Subject())
+	require.NotNil(t, result.Deploy)
+	require.NoError(t, result.Deploy.Err)
+
+	content, err := os.ReadFile(filepath.Join(localDir, "init.lua"))
+	require.NoError(t, err)
+	assert.Equal(t, "-- good\n", string(content))
+
+	// The rollback is recorded as a new commit and the store is clean
+	commits, err := repo.Log("nvim", 0)
+	require.NoError(t, err)
+	require.Len(t, commits, 3)
+	assert.Equal(t, "Rollback nvim", commits[0].Subject())
+	status, err := repo.Status()
+	require.NoError(t, err)
+	assert.Empty(t, status)
+
+	// A time before the first commit has nothing to restore
+	_, err = app.RestoreDotfile("nvim", "", time.Now().Add(-time.Hour), false)
+	assert.ErrorIs(t, err, git.ErrRevisionNotFound)
+}
+
+func TestParseRestoreTime(t *testing.T) {
+	at, err := ParseRestoreTime("2026-09-01")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 9, 1, 0, 0, 0, 0, time.Local), at)
+
+	at, err = ParseRestoreTime("2026-09-01 18:30")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 9, 1, 18, 30, 0, 0, time.Local), at)
+
+	_, err = ParseRestoreTime("yesterday")
+	assert.Error(t, err)
+}
@@ -0,0 +1,148 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/bnema/gart/internal/security"
+)
+
+// ScanTarget is a file or directory checked by a standalone security scan
+type ScanTarget struct {
+	// Name is the dotfile name, empty for the store and arbitrary paths
+	Name    string
+	Path    string
+	Ignores []string
+}
+
+// ScanTargets resolves the arguments of a scan, each being a dotfile name or a path. With
+// no arguments every dotfile found on this host is scanned, except encrypted ones whose
+// local copy is expected to hold secrets. store adds the whole store to the targets.
+func (app *App) ScanTargets(args []string, store bool) ([]ScanTarget, error) {
+	var targets []ScanTarget
+	if store {
+		target := ScanTarget{Path: app.StoragePath}
+		// Encrypted entries only hold ciphertext
+		for name := range app.Config.DotfilesEncrypt {
+			if rel, err := app.storeRelPath(name); err == nil {
+				target.Ignores = append(target.Ignores, rel)
+			}
+		}
+		sort.Strings(target.Ignores)
+		targets = append(targets, target)
+	}
+
+	if len(args) == 0 && !store {
+		names := make([]string, 0, len(app.Config.Dotfiles))
+		for name := range app.Config.Dotfiles {
+			if !app.IsEncrypted(name) {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			target := app.dotfileScanTarget(name)
+			if _, err := os.Stat(target.Path); err == nil {
+				targets = append(targets, target)
+			}
+		}
+		return targets, nil
+	}
+
+	for _, arg := range args {
+		if _, ok := app.Config.Dotfiles[arg]; ok {
+			targets = append(targets, app.dotfileScanTarget(arg))
+			continue
+		}
+
+		path, err := filepath.Abs(app.ExpandHomeDir(arg))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("'%s' is neither a dotfile nor an existing path", arg)
+		}
+		targets = append(targets, ScanTarget{Path: path})
+	}
+	return targets, nil
+}
+
+// dotfileScanTarget scans the files a sync would scan: the local copy, or the store entry
+// of a symlinked dotfile
+func (app *App) dotfileScanTarget(name string) ScanTarget {
+	target := ScanTarget{
+		Name:    name,
+		Path:    app.ExpandHomeDir(app.Config.Dotfiles[name]),
+		Ignores: app.Config.DotfilesIgnores[name],
+	}
+	if app.IsSymlinkMode(name) {
+		target.Path = app.StorePathFor(name, target.Path)
+	}
+	return target
+}
+
+// Scan runs the security scanner on the targets and merges their reports, leaving out the
+// findings accepted in the baseline. The scan uses the security settings but runs even
+// when the checks of add and sync are disabled.
+func (app *App) Scan(targets []ScanTarget) (*security.ScanReport, error) {
+	config := security.DefaultSecurityConfig()
+	if app.Config.Settings.Security != nil {
+		settings := *app.Config.Settings.Security
+		config = &settings
+	}
+	config.Enabled = true
+	securityContext, err := app.NewSecurityContext(config)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &security.ScanReport{Results: []security.ScanResult{}}
+	for _, target := range targets {
+		scan, err := securityContext.ScanPath(target.Path, target.Ignores)
+		if err != nil {
+			return nil, fmt.Errorf("security scan failed: %w", err)
+		}
+		report.Merge(scan)
+	}
+	return report, nil
+}
+
+// TestRule runs the user-defined rule with the given ID alone on the targets and returns
+// every match, including those the rule leaves out
+func (app *App) TestRule(id string, targets []ScanTarget) ([]security.RuleMatch, error) {
+	rule, err := app.findRule(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []security.RuleMatch
+	for _, target := range targets {
+		found, err := rule.TestPath(target.Path, target.Ignores)
+		if err != nil {
+			return nil, fmt.Errorf("error testing rule on %s: %w", target.Path, err)
+		}
+		matches = append(matches, found...)
+	}
+	return matches, nil
+}
+
+// TestRuleContent runs the user-defined rule with the given ID alone on content
+func (app *App) TestRuleContent(id string, content []byte) ([]security.RuleMatch, error) {
+	rule, err := app.findRule(id)
+	if err != nil {
+		return nil, err
+	}
+	return rule.TestContent(content, "-")
+}
+
+func (app *App) findRule(id string) (security.Rule, error) {
+	if app.Config.Settings.Security != nil {
+		if rule, ok := app.Config.Settings.Security.FindRule(id); ok {
+			return rule, nil
+		}
+	}
+	return security.Rule{}, fmt.Errorf("security rule '%s' not found in [[settings.security.rules]]", id)
+}
@@ -0,0 +1,191 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/bnema/gart/internal/git"
+	"github.com/bnema/gart/internal/system"
+)
+
+// Drift states of a dotfile
+const (
+	DriftInSync        = "in-sync"
+	DriftLocalModified = "local-modified"
+	DriftStoreModified = "store-modified"
+	// DriftBothModified means both sides changed since the last sync
+	DriftBothModified = "both-modified"
+	// DriftModified means the sides differ but the dotfile has no recorded sync to tell which changed
+	DriftModified     = "modified"
+	DriftMissingLocal = "missing-local"
+	DriftMissingStore = "missing-store"
+	DriftNotLinked    = "not-linked"
+	DriftUnknown      = "unknown"
+	DriftError        = "error"
+)
+
+// DotfileStatus describes how far the local path and the store entry of a dotfile drifted
+type DotfileStatus struct {
+	Name      string
+	LocalPath string
+	StorePath string
+	// State is one of the Drift constants
+	State string
+	// LocalChanges and StoreChanges are the files changed on each side since the last sync,
+	// relative to the dotfile. Without a recorded sync LocalChanges lists the differing files.
+	LocalChanges []string
+	StoreChanges []string
+	// Conflicts are the files changed on both sides
+	Conflicts []string
+	// Uncommitted are the files of the store entry with changes not committed yet,
+	// relative to the store root
+	Uncommitted []string
+	Err         error
+}
+
+// StoreStatus describes the git repository of the store
+type StoreStatus struct {
+	Versioning bool
+	// Uncommitted are the changed files relative to the store root
+	Uncommitted []string
+	// Branch compares the current branch with the remote, nil without a remote
+	Branch *git.BranchStatus
+}
+
+// StoreStatus reads the uncommitted files of the store and how far its branch is from the
+// remote as of the last fetch. fetch updates the remote branches first.
+func (app *App) StoreStatus(fetch bool) (StoreStatus, error) {
+	status := StoreStatus{Versioning: app.Config.Settings.GitVersioning}
+	if !status.Versioning {
+		return status, nil
+	}
+
+	repo, err := app.getOrCreateGitRepository()
+	if err != nil {
+		return status, err
+	}
+
+	if fetch {
+		if err := repo.Fetch(); err != nil && !git.IsNoRemoteError(err) {
+			return status, err
+		}
+	}
+
+	files, err := repo.Status()
+	if err != nil {
+		return status, err
+	}
+	sort.Strings(files)
+	status.Uncommitted = files
+
+	branch, err := repo.BranchStatus()
+	if err != nil && !git.IsNoRemoteError(err) {
+		return status, err
+	}
+	status.Branch = branch
+	return status, nil
+}
+
+// DotfileStatus compares both sides of a dotfile with their state at the last sync without
+// changing anything. store provides the uncommitted files of the store entry.
+func (app *App) DotfileStatus(name string, store StoreStatus) DotfileStatus {
+	status := DotfileStatus{Name: name}
+	fail := func(err error) DotfileStatus {
+		status.State = DriftError
+		status.Err = err
+		return status
+	}
+
+	path, ok := app.Config.Dotfiles[name]
+	if !ok {
+		return fail(fmt.Errorf("dotfile '%s' not found", name))
+	}
+	status.LocalPath = app.ExpandHomeDir(path)
+	status.StorePath = app.StorePathFor(name, status.LocalPath)
+
+	if rel, err := app.storeRelPath(name); err == nil {
+		for _, file := range store.Uncommitted {
+			if file == rel || strings.HasPrefix(file, rel+"/") {
+				status.Uncommitted = append(status.Uncommitted, file)
+			}
+		}
+	}
+
+	if _, err := os.Lstat(status.LocalPath); os.IsNotExist(err) {
+		status.State = DriftMissingLocal
+		return status
+	} else if err != nil {
+		return fail(err)
+	}
+	if _, err := os.Stat(status.StorePath); os.IsNotExist(err) {
+		status.State = DriftMissingStore
+		return status
+	} else if err != nil {
+		return fail(err)
+	}
+
+	// Symlinked dotfiles are edited in the store, only the link can drift
+	if app.IsSymlinkMode(name) {
+		linked, err := system.IsSymlinkTo(status.LocalPath, status.StorePath)
+		if err != nil {
+			return fail(err)
+		}
+		status.State = DriftInSync
+		if !linked {
+			status.State = DriftNotLinked
+		}
+		return status
+	}
+
+	manifest, err := app.Manifest()
+	if err != nil {
+		return fail(err)
+	}
+	ignores := app.Config.DotfilesIgnores[name]
+
+	last, ok := manifest.LastSync(name)
+	if !ok {
+		// Templated and encrypted sides only compare through a recorded sync
+		if app.IsTemplate(name) || app.IsEncrypted(name) {
+			status.State = DriftUnknown
+			return status
+		}
+		if status.LocalChanges, err = system.DifferingFiles(status.LocalPath, status.StorePath, ignores, manifest); err != nil {
+			return fail(fmt.Errorf("error comparing '%s': %w", name, err))
+		}
+		status.State = DriftInSync
+		if len(status.LocalChanges) > 0 {
+			status.State = DriftModified
+		}
+		return status
+	}
+
+	status.LocalChanges, status.StoreChanges, err = system.ChangesSince(status.LocalPath, status.StorePath, last, ignores, manifest)
+	if err != nil {
+		return fail(fmt.Errorf("error comparing '%s': %w", name, err))
+	}
+
+	storeChanged := make(map[string]bool, len(status.StoreChanges))
+	for _, file := range status.StoreChanges {
+		storeChanged[file] = true
+	}
+	for _, file := range status.LocalChanges {
+		if storeChanged[file] {
+			status.Conflicts = append(status.Conflicts, file)
+		}
+	}
+
+	switch {
+	case len(status.LocalChanges) > 0 && len(status.StoreChanges) > 0:
+		status.State = DriftBothModified
+	case len(status.LocalChanges) > 0:
+		status.State = DriftLocalModified
+	case len(status.StoreChanges) > 0:
+		status.State = DriftStoreModified
+	default:
+		status.State = DriftInSync
+	}
+	return status
+}
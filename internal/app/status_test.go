@@ -0,0 +1,81 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bnema/gart/internal/config"
+	"github.com/bnema/gart/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApp_DotfileStatus(t *testing.T) {
+	tempDir := t.TempDir()
+	storeDir := filepath.Join(tempDir, "store")
+	localDir := filepath.Join(tempDir, "home", ".config", "nvim")
+	require.NoError(t, os.MkdirAll(storeDir, 0755))
+	require.NoError(t, os.MkdirAll(localDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(localDir, "init.lua"), []byte("-- v1\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(localDir, "keys.lua"), []byte("-- v1\n"), 0644))
+
+	repo, err := git.NewRepository(storeDir)
+	require.NoError(t, err)
+	require.NoError(t, repo.Init("main"))
+
+	app := &App{
+		StoragePath: storeDir,
+		Config: &config.Config{
+			Dotfiles: map[string]string{
+				"nvim":    localDir,
+				"missing": filepath.Join(tempDir, "home", ".config", "missing"),
+			},
+			DotfilesIgnores: map[string][]string{},
+			Settings: config.SettingsConfig{
+				GitVersioning: true,
+				Git:           config.GitConfig{CommitMessageFormat: "{{ .Action }} {{ .Dotfile }}"},
+				Backup:        &config.BackupConfig{Enabled: false},
+			},
+		},
+		gitRepo: repo,
+	}
+
+	storePath := filepath.Join(storeDir, "nvim")
+	_, err = app.SyncDotfile("nvim", localDir, storePath, nil, nil, false)
+	require.NoError(t, err)
+
+	store, err := app.StoreStatus(false)
+	require.NoError(t, err)
+	assert.Empty(t, store.Uncommitted)
+	assert.Nil(t, store.Branch)
+
+	status := app.DotfileStatus("nvim", store)
+	require.NoError(t, status.Err)
+	assert.Equal(t, DriftInSync, status.State)
+
+	status = app.DotfileStatus("missing", store)
+	assert.Equal(t, DriftMissingLocal, status.State)
+
+	// A local edit
+	require.NoError(t, os.WriteFile(filepath.Join(localDir, "init.lua"), []byte("-- local\n"), 0644))
+	status = app.DotfileStatus("nvim", store)
+	assert.Equal(t, DriftLocalModified, status.State)
+	assert.Equal(t, []string{"init.lua"}, status.LocalChanges)
+
+	// A store edit of the same file, not committed yet
+	require.NoError(t, os.WriteFile(filepath.Join(storePath, "init.lua"), []byte("-- store\n"), 0644))
+	store, err = app.StoreStatus(false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"nvim/init.lua"}, store.Uncommitted)
+
+	status = app.DotfileStatus("nvim", store)
+	assert.Equal(t, DriftBothModified, status.State)
+	assert.Equal(t, []string{"init.lua"}, status.Conflicts)
+	assert.Equal(t, []string{"nvim/init.lua"}, status.Uncommitted)
+
+	// Status never writes to either side
+	content, err := os.ReadFile(filepath.Join(localDir, "init.lua"))
+	require.NoError(t, err)
+	assert.Equal(t, "-- local\n", string(content))
+}
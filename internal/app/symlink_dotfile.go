@@ -0,0 +1,261 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bnema/gart/internal/config"
+	"github.com/bnema/gart/internal/system"
+)
+
+// IsSymlinkMode reports whether a dotfile is managed through a symlink into the store
+func (app *App) IsSymlinkMode(name string) bool {
+	return app.Config.DotfileMode(name) == config.ModeSymlink
+}
+
+// AddDotfileSymlink moves a dotfile into the store and replaces it with a symlink
+func (app *App) AddDotfileSymlink(path, dotfileName string, ignores []string) error {
+	path = filepath.Clean(app.ExpandHomeDir(path))
+
+	info, err := os.Lstat(path)
+	if err != nil {
+		return fmt.Errorf("error accessing %s: %w", path, err)
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		return fmt.Errorf("%s is already a symlink", path)
+	}
+
+	storePath := app.StorePathFor(dotfileName, path)
+	if _, err := os.Lstat(storePath); err == nil {
+		return fmt.Errorf("store entry %s already exists", storePath)
+	}
+
+	if err := system.MovePath(path, storePath); err != nil {
+		return fmt.Errorf("error moving dotfile into store: %w", err)
+	}
+
+	if err := os.Symlink(storePath, path); err != nil {
+		// Put the original back so the user does not lose their file
+		if moveErr := system.MovePath(storePath, path); moveErr != nil {
+			return fmt.Errorf("error creating symlink: %w (restoring original also failed: %v)", err, moveErr)
+		}
+		return fmt.Errorf("error creating symlink: %w", err)
+	}
+
+	app.Dotfile.Name = dotfileName
+	app.Dotfile.Path = path
+
+	if err := app.UpdateConfig(dotfileName, path, ignores); err != nil {
+		return err
+	}
+	if err := app.SetDotfileMode(dotfileName, config.ModeSymlink); err != nil {
+		return err
+	}
+	return app.UpdateStoreGitignore()
+}
+
+// LinkDotfile creates the symlink from the local path of a dotfile to its store entry.
+// An existing file at the local path is never overwritten.
+func (app *App) LinkDotfile(name string) error {
+	path, ok := app.Config.Dotfiles[name]
+	if !ok {
+		return fmt.Errorf("dotfile '%s' not found", name)
+	}
+	path = app.ExpandHomeDir(path)
+	storePath := app.StorePathFor(name, path)
+
+	if _, err := os.Stat(storePath); err != nil {
+		return fmt.Errorf("store entry not found: %w", err)
+	}
+
+	linked, err := system.IsSymlinkTo(path, storePath)
+	if err != nil {
+		return fmt.Errorf("error checking %s: %w", path, err)
+	}
+	if linked {
+		return nil
+	}
+
+	if _, err := os.Lstat(path); err == nil {
+		return fmt.Errorf("refusing to replace existing %s with a symlink", path)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create parent directory: %w", err)
+	}
+
+	if err := os.Symlink(storePath, path); err != nil {
+		return fmt.Errorf("error creating symlink: %w", err)
+	}
+	return nil
+}
+
+// UnlinkDotfile replaces the symlink of a dotfile with a real copy of its store entry
+// and switches the dotfile back to copy mode
+func (app *App) UnlinkDotfile(name string) error {
+	path, ok := app.Config.Dotfiles[name]
+	if !ok {
+		return fmt.Errorf("dotfile '%s' not found", name)
+	}
+	path = app.ExpandHomeDir(path)
+	storePath := app.StorePathFor(name, path)
+
+	linked, err := system.IsSymlinkTo(path, storePath)
+	if err != nil {
+		return fmt.Errorf("error checking %s: %w", path, err)
+	}
+
+	if linked {
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("error removing symlink: %w", err)
+		}
+		// The store holds the only copy of the ignored files, they move back to the local path
+		if err := system.CopyPath(storePath, path, nil); err != nil {
+			return fmt.Errorf("failed to copy %s to %s: %w", storePath, path, err)
+		}
+		if err := system.RemoveIgnoredFiles(storePath, app.Config.DotfilesIgnores[name]); err != nil {
+			return fmt.Errorf("error removing ignored files from the store: %w", err)
+		}
+	}
+
+	if err := app.SetDotfileMode(name, config.ModeCopy); err != nil {
+		return err
+	}
+	return app.UpdateStoreGitignore()
+}
+
+// ConvertToSymlink switches an existing copy mode dotfile to symlink mode.
+// The local content replaces the store entry, which must not hold changes missing locally,
+// then the local path is replaced by a symlink.
+func (app *App) ConvertToSymlink(name string) error {
+	path, ok := app.Config.Dotfiles[name]
+	if !ok {
+		return fmt.Errorf("dotfile '%s' not found", name)
+	}
+	if app.IsEncrypted(name) {
+		return fmt.Errorf("dotfile '%s' is stored encrypted and cannot be symlinked", name)
+	}
+	if app.IsTemplate(name) {
+		return fmt.Errorf("dotfile '%s' is a template and cannot be symlinked", name)
+	}
+	path = app.ExpandHomeDir(path)
+	storePath := app.StorePathFor(name, path)
+
+	info, err := os.Lstat(path)
+	if err != nil {
+		return fmt.Errorf("error accessing %s: %w", path, err)
+	}
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		if err := app.LinkDotfile(name); err != nil {
+			return err
+		}
+	} else if err := app.moveIntoStore(name, path, storePath); err != nil {
+		return err
+	}
+	if err := app.SetDotfileMode(name, config.ModeSymlink); err != nil {
+		return err
+	}
+	return app.UpdateStoreGitignore()
+}
+
+// UpdateStoreGitignore keeps the ignored files of symlinked dotfiles out of the store repository.
+// Their whole tree lives in the store, so their ignores are written to the store .gitignore.
+func (app *App) UpdateStoreGitignore() error {
+	var names []string
+	for name := range app.Config.Dotfiles {
+		if app.IsSymlinkMode(name) && len(app.Config.DotfilesIgnores[name]) > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var patterns []string
+	for _, name := range names {
+		rel, err := app.storeRelPath(name)
+		if err != nil {
+			return err
+		}
+		patterns = append(patterns, system.GitignorePatterns(rel, app.Config.DotfilesIgnores[name])...)
+	}
+
+	if err := system.WriteGitignoreSection(filepath.Join(app.StoragePath, ".gitignore"), patterns); err != nil {
+		return fmt.Errorf("error updating the store .gitignore: %w", err)
+	}
+	return nil
+}
+
+// moveIntoStore replaces the store entry of a copy mode dotfile with its local content and
+// links the local path to it. The previous store entry is kept aside until the link exists
+// and put back on failure.
+func (app *App) moveIntoStore(name, path, storePath string) error {
+	if _, err := os.Lstat(storePath); err != nil {
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("error accessing %s: %w", storePath, err)
+		}
+		if err := system.MovePath(path, storePath); err != nil {
+			return fmt.Errorf("error moving dotfile into store: %w", err)
+		}
+		return app.LinkDotfile(name)
+	}
+
+	manifest, err := app.Manifest()
+	if err != nil {
+		return err
+	}
+	differing, err := system.DifferingFiles(path, storePath, app.Config.DotfilesIgnores[name], manifest)
+	if err != nil {
+		return fmt.Errorf("error comparing %s with the store: %w", path, err)
+	}
+	if len(differing) > 0 {
+		return fmt.Errorf("dotfile '%s' differs from its store entry (%s), run 'gart sync %s' first",
+			name, strings.Join(differing, ", "), name)
+	}
+
+	asideDir, err := os.MkdirTemp(filepath.Dir(storePath), ".gart-mode-")
+	if err != nil {
+		return fmt.Errorf("error creating temporary directory: %w", err)
+	}
+	aside := filepath.Join(asideDir, filepath.Base(storePath))
+	if err := os.Rename(storePath, aside); err != nil {
+		_ = os.Remove(asideDir)
+		return fmt.Errorf("error moving store entry aside: %w", err)
+	}
+
+	restore := func(cause error) error {
+		if restoreErr := os.Rename(aside, storePath); restoreErr != nil {
+			return fmt.Errorf("%w (restoring the store entry from %s also failed: %v)", cause, aside, restoreErr)
+		}
+		_ = os.Remove(asideDir)
+		return cause
+	}
+
+	if err := system.MovePath(path, storePath); err != nil {
+		return restore(fmt.Errorf("error moving dotfile into store: %w", err))
+	}
+	if err := app.LinkDotfile(name); err != nil {
+		if moveErr := system.MovePath(storePath, path); moveErr != nil {
+			return fmt.Errorf("%w (moving %s back to %s also failed: %v)", err, storePath, path, moveErr)
+		}
+		return restore(err)
+	}
+
+	return system.RemoveDirectory(asideDir)
+}
+
+// SetDotfileMode updates the management mode of a dotfile in memory and in the config file
+func (app *App) SetDotfileMode(name, mode string) error {
+	if err := config.ValidateMode(mode); err != nil {
+		return err
+	}
+
+	if app.Config.DotfilesModes == nil {
+		app.Config.DotfilesModes = make(map[string]string)
+	}
+	app.Config.DotfilesModes[name] = mode
+
+	return config.UpdateDotfileMode(app.ConfigFilePath, name, mode)
+}
@@ -0,0 +1,204 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bnema/gart/internal/config"
+	"github.com/bnema/gart/internal/git"
+	"github.com/bnema/gart/internal/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupSymlinkApp(t *testing.T) (*App, string) {
+	tempDir := t.TempDir()
+	storeDir := filepath.Join(tempDir, "store")
+	homeDir := filepath.Join(tempDir, "home")
+	configPath := filepath.Join(tempDir, "config.toml")
+
+	require.NoError(t, os.MkdirAll(storeDir, 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(homeDir, ".config", "kitty"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(homeDir, ".config", "kitty", "kitty.conf"), []byte("font_size 12"), 0644))
+
+	cfg := &config.Config{
+		Settings: config.SettingsConfig{StoragePath: storeDir},
+		Dotfiles: make(map[string]string),
+	}
+	require.NoError(t, config.SaveConfig(configPath, cfg))
+
+	app := &App{
+		ConfigFilePath: configPath,
+		StoragePath:    storeDir,
+		Config:         cfg,
+	}
+	return app, homeDir
+}
+
+func TestApp_AddDotfileSymlink(t *testing.T) {
+	app, homeDir := setupSymlinkApp(t)
+	localPath := filepath.Join(homeDir, ".config", "kitty")
+	storePath := filepath.Join(app.StoragePath, "kitty")
+
+	err := app.AddDotfileSymlink(localPath, "kitty", nil)
+	require.NoError(t, err)
+
+	linked, err := system.IsSymlinkTo(localPath, storePath)
+	require.NoError(t, err)
+	assert.True(t, linked, "Local path should be a symlink to the store")
+
+	content, err := os.ReadFile(filepath.Join(storePath, "kitty.conf"))
+	require.NoError(t, err)
+	assert.Equal(t, "font_size 12", string(content))
+
+	assert.True(t, app.IsSymlinkMode("kitty"))
+
+	// The mode must be persisted in the config file
+	cfg, err := config.LoadConfig(app.ConfigFilePath)
+	require.NoError(t, err)
+	assert.Equal(t, config.ModeSymlink, cfg.DotfileMode("kitty"))
+
+	// Adding the same path again must fail instead of overwriting the store
+	err = app.AddDotfileSymlink(localPath, "kitty", nil)
+	assert.Error(t, err)
+}
+
+func TestApp_LinkDotfile_RefusesExistingFile(t *testing.T) {
+	app, homeDir := setupSymlinkApp(t)
+	localPath := filepath.Join(homeDir, ".config", "kitty")
+
+	require.NoError(t, app.AddDotfileSymlink(localPath, "kitty", nil))
+
+	// Simulate a fresh machine where the path is missing
+	require.NoError(t, os.Remove(localPath))
+	result := app.DeployDotfile("kitty")
+	require.NoError(t, result.Err)
+
+	linked, err := system.IsSymlinkTo(localPath, filepath.Join(app.StoragePath, "kitty"))
+	require.NoError(t, err)
+	assert.True(t, linked, "Deploy should recreate the symlink")
+
+	// A real directory at the local path must never be replaced
+	require.NoError(t, os.Remove(localPath))
+	require.NoError(t, os.MkdirAll(localPath, 0755))
+	err = app.LinkDotfile("kitty")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "refusing to replace")
+}
+
+func TestApp_UnlinkDotfile(t *testing.T) {
+	app, homeDir := setupSymlinkApp(t)
+	localPath := filepath.Join(homeDir, ".config", "kitty")
+
+	require.NoError(t, app.AddDotfileSymlink(localPath, "kitty", nil))
+	require.NoError(t, app.UnlinkDotfile("kitty"))
+
+	info, err := os.Lstat(localPath)
+	require.NoError(t, err)
+	assert.True(t, info.IsDir(), "Local path should be a real directory again")
+	assert.False(t, app.IsSymlinkMode("kitty"))
+
+	content, err := os.ReadFile(filepath.Join(localPath, "kitty.conf"))
+	require.NoError(t, err)
+	assert.Equal(t, "font_size 12", string(content))
+
+	// Converting back must link to the store again
+	require.NoError(t, app.ConvertToSymlink("kitty"))
+	linked, err := system.IsSymlinkTo(localPath, filepath.Join(app.StoragePath, "kitty"))
+	require.NoError(t, err)
+	assert.True(t, linked)
+}
+
+func TestApp_SymlinkIgnoresStayOutOfGit(t *testing.T) {
+	app, homeDir := setupSymlinkApp(t)
+	localPath := filepath.Join(homeDir, ".config", "kitty")
+	storePath := filepath.Join(app.StoragePath, "kitty")
+	require.NoError(t, os.MkdirAll(filepath.Join(localPath, "cache"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(localPath, "cache", "glyphs.bin"), []byte("v1"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(localPath, "kitty.log"), []byte("v1"), 0644))
+
+	repo, err := git.NewRepository(app.StoragePath)
+	require.NoError(t, err)
+	require.NoError(t, repo.Init("main"))
+	app.gitRepo = repo
+	app.Config.Settings.GitVersioning = true
+	app.Config.Settings.Git.CommitMessageFormat = "{{ .Action }} {{ .Dotfile }}"
+
+	require.NoError(t, app.AddDotfileSymlink(localPath, "kitty", []string{"cache/", "*.log"}))
+	require.NoError(t, app.GitCommitChanges("Add", "kitty"))
+
+	gitignore, err := os.ReadFile(filepath.Join(app.StoragePath, ".gitignore"))
+	require.NoError(t, err)
+	assert.Contains(t, string(gitignore), "/kitty/**/cache/\n/kitty/**/*.log\n")
+
+	// Editing ignored files through the link must not show up as store changes
+	require.NoError(t, os.WriteFile(filepath.Join(localPath, "cache", "glyphs.bin"), []byte("v2"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(localPath, "kitty.log"), []byte("v2"), 0644))
+	status, err := repo.Status()
+	require.NoError(t, err)
+	assert.Empty(t, status, "ignored files must not be tracked")
+
+	// Back in copy mode, the ignored files live in the local copy only
+	require.NoError(t, app.UnlinkDotfile("kitty"))
+	content, err := os.ReadFile(filepath.Join(localPath, "cache", "glyphs.bin"))
+	require.NoError(t, err)
+	assert.Equal(t, "v2", string(content))
+	assert.NoFileExists(t, filepath.Join(storePath, "cache", "glyphs.bin"))
+	assert.NoFileExists(t, filepath.Join(storePath, "kitty.log"))
+	assert.NoFileExists(t, filepath.Join(app.StoragePath, ".gitignore"))
+}
+
+func TestApp_ConvertToSymlink_KeepsUnsyncedStoreChanges(t *testing.T) {
+	app, homeDir := setupSymlinkApp(t)
+	localPath := filepath.Join(homeDir, ".config", "kitty")
+	storePath := filepath.Join(app.StoragePath, "kitty")
+
+	require.NoError(t, app.AddDotfileSymlink(localPath, "kitty", nil))
+	require.NoError(t, app.UnlinkDotfile("kitty"))
+
+	// A change pulled into the store was never synced to the local copy
+	require.NoError(t, os.WriteFile(filepath.Join(storePath, "kitty.conf"), []byte("font_size 14"), 0644))
+
+	err := app.ConvertToSymlink("kitty")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "kitty.conf")
+	assert.False(t, app.IsSymlinkMode("kitty"))
+
+	content, err := os.ReadFile(filepath.Join(storePath, "kitty.conf"))
+	require.NoError(t, err)
+	assert.Equal(t, "font_size 14", string(content), "the store entry must be left untouched")
+	info, err := os.Lstat(localPath)
+	require.NoError(t, err)
+	assert.True(t, info.IsDir())
+
+	// Once both sides match the store entry is replaced and nothing is left aside
+	require.NoError(t, os.WriteFile(filepath.Join(localPath, "kitty.conf"), []byte("font_size 14"), 0644))
+	require.NoError(t, app.ConvertToSymlink("kitty"))
+	linked, err := system.IsSymlinkTo(localPath, storePath)
+	require.NoError(t, err)
+	assert.True(t, linked)
+
+	entries, err := os.ReadDir(app.StoragePath)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		assert.NotContains(t, entry.Name(), ".gart-mode-")
+	}
+}
+
+func TestApp_ConvertToSymlink_RejectsTemplates(t *testing.T) {
+	app, homeDir := setupSymlinkApp(t)
+	localPath := filepath.Join(homeDir, ".config", "kitty")
+
+	require.NoError(t, app.UpdateConfig("kitty", localPath, nil))
+	require.NoError(t, app.SetDotfileTemplate("kitty", true))
+
+	err := app.ConvertToSymlink("kitty")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "template")
+
+	info, err := os.Lstat(localPath)
+	require.NoError(t, err)
+	assert.True(t, info.IsDir(), "the local directory must be left in place")
+	assert.False(t, app.IsSymlinkMode("kitty"))
+}
@@ -0,0 +1,100 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// SyncJobs returns the number of dotfiles to sync concurrently
+func (app *App) SyncJobs() int {
+	if app.Config.Settings.SyncJobs > 0 {
+		return app.Config.Settings.SyncJobs
+	}
+	return runtime.NumCPU()
+}
+
+// SyncBatch syncs dotfiles with up to jobs concurrent workers, SyncJobs when jobs is not
+// positive. The callbacks of opts and done, called after each dotfile, never run
+// concurrently. Pushed changes are recorded in a single commit for the whole batch.
+// Reports are returned in the order of names.
+func (app *App) SyncBatch(names []string, opts SyncOptions, jobs int, done func(SyncReport)) []SyncReport {
+	if jobs <= 0 {
+		jobs = app.SyncJobs()
+	}
+	opts.deferCommit = true
+	opts.prompt = &sync.Mutex{}
+
+	// A passphrase prompt must not run while a worker prompts
+	for _, name := range names {
+		if app.IsEncrypted(name) {
+			_, _ = app.Encrypter()
+			break
+		}
+	}
+
+	reports := make([]SyncReport, len(names))
+	var aborted atomic.Bool
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(jobs, len(names)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				report := SyncReport{SyncResult: SyncResult{Name: names[i], Reverse: opts.Reverse}, Status: SyncStatusSkipped, Reason: ErrSyncAborted.Error()}
+				if !aborted.Load() {
+					report = app.SyncUnattended(names[i], opts)
+				}
+				if errors.Is(report.Err, ErrSyncAborted) {
+					aborted.Store(true)
+				}
+				reports[i] = report
+				if done != nil {
+					opts.ask(func() { done(report) })
+				}
+			}
+		}()
+	}
+	for i := range names {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	app.commitBatch(reports)
+	return reports
+}
+
+// commitBatch records the pushed changes of a batch in one commit and sets its hash on the
+// reports, or marks them failed when the commit fails
+func (app *App) commitBatch(reports []SyncReport) {
+	var names []string
+	for _, report := range reports {
+		if report.pendingCommit {
+			names = append(names, report.Name)
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+
+	before := app.headCommit("")
+	err := app.gitCommit("Update", strings.Join(names, ", "), names)
+	commit := app.headCommit("")
+	for i := range reports {
+		if !reports[i].pendingCommit {
+			continue
+		}
+		reports[i].pendingCommit = false
+		if err != nil {
+			reports[i].Status = SyncStatusFailed
+			reports[i].Err = fmt.Errorf("error committing changes: %w", err)
+		} else if commit != before {
+			reports[i].Commit = commit
+		}
+	}
+}
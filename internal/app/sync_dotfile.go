@@ -0,0 +1,282 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bnema/gart/internal/config"
+	"github.com/bnema/gart/internal/system"
+)
+
+// SyncResult describes the outcome of syncing a dotfile
+type SyncResult struct {
+	Name string
+	// Reverse is set when the store was copied to the local path
+	Reverse bool
+	// Files are the changed files relative to the dotfile, empty when nothing changed
+	Files []string
+	// Changes are the changed files with the type of change
+	Changes []system.TargetChange
+	// Commit is the hash of the commit recording pushed changes, empty without one
+	Commit string
+	// Backup holds the local files overwritten by the sync
+	Backup *Backup
+
+	// storeChanged is set when the sync wrote to the store
+	storeChanged bool
+}
+
+// Changed reports whether the sync changed any file
+func (r SyncResult) Changed() bool {
+	return len(r.Files) > 0
+}
+
+// SyncStorePath returns the store entry a dotfile at localPath is synced with
+func (app *App) SyncStorePath(name, localPath string) (string, error) {
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return "", fmt.Errorf("error accessing source path: %w", err)
+	}
+
+	// Directories use the dotfile name as is, files reuse the existing store entry
+	// or include the file extension
+	if info.IsDir() {
+		return filepath.Join(app.StoragePath, name), nil
+	}
+	return app.StorePathFor(name, localPath), nil
+}
+
+// SyncDotfile copies a dotfile from its local path to the store, or from the store to the
+// local path when reverse is set. Files changed since the last sync on the destination side
+// only are copied the other way first, so the direction only decides for files changed on
+// both sides. Changes to the store are committed and the post_sync hooks run when files
+// changed. Files listed in exclude, e.g. with security findings, are left untouched on both
+// sides. Security scans and conflict checks are left to the caller.
+func (app *App) SyncDotfile(name, localPath, storePath string, ignores, exclude []string, reverse bool) (SyncResult, error) {
+	return app.syncDotfile(name, localPath, storePath, ignores, exclude, reverse, true)
+}
+
+// syncDotfile implements SyncDotfile, commit is unset when a batch commits the changes
+func (app *App) syncDotfile(name, localPath, storePath string, ignores, exclude []string, reverse, commit bool) (SyncResult, error) {
+	result := SyncResult{Name: name, Reverse: reverse}
+
+	if reverse {
+		if _, err := os.Stat(storePath); os.IsNotExist(err) {
+			return result, fmt.Errorf("store file doesn't exist for reverse sync: %s", storePath)
+		}
+	}
+
+	taken, err := app.syncOneSided(&result, localPath, storePath, ignores, exclude)
+	if err != nil {
+		return result, err
+	}
+
+	// Templated and encrypted dotfiles are compared through a staged copy
+	localSide, storeSide, cleanup, err := app.SyncSides(name, localPath, storePath, reverse)
+	if err != nil {
+		return result, fmt.Errorf("error preparing '%s': %w", name, err)
+	}
+	defer cleanup()
+
+	// Reuse cached hashes of the real files, staged copies are fresh on every run
+	var manifest *system.Manifest
+	if localSide == localPath && storeSide == storePath {
+		if manifest, err = app.Manifest(); err != nil {
+			return result, fmt.Errorf("error loading manifest: %w", err)
+		}
+	}
+
+	// A new directory is planned as a single copy, create it so exclusions apply per file
+	if len(exclude) > 0 {
+		origin, destination := localSide, storeSide
+		if reverse {
+			origin, destination = storeSide, localSide
+		}
+		if info, err := os.Stat(origin); err == nil && info.IsDir() {
+			if _, err := os.Stat(destination); os.IsNotExist(err) {
+				if err := os.MkdirAll(destination, 0755); err != nil {
+					return result, fmt.Errorf("error creating %s: %w", destination, err)
+				}
+			}
+		}
+	}
+
+	plan, err := system.PlanSyncWithManifest(localSide, storeSide, ignores, reverse, manifest)
+	if err != nil {
+		return result, fmt.Errorf("error comparing dotfiles: %w", err)
+	}
+	if len(exclude) > 0 {
+		plan = plan.Excluding(exclude)
+	}
+
+	// Snapshot local files before the store overwrites them
+	if reverse {
+		if result.Backup, err = app.BackupPlanTargets(name, "reverse-sync", plan); err != nil {
+			return result, fmt.Errorf("error backing up local files: %w", err)
+		}
+	}
+
+	if err := system.ApplyPlan(plan, ignores); err != nil {
+		return result, fmt.Errorf("error copying dotfiles: %w", err)
+	}
+
+	if !plan.IsEmpty() {
+		destination := storeSide
+		if reverse {
+			destination = localSide
+		}
+		result.Changes = append(taken, plan.TargetChanges(destination, ignores)...)
+		result.storeChanged = result.storeChanged || !reverse
+	} else {
+		result.Changes = taken
+	}
+	for _, change := range result.Changes {
+		result.Files = append(result.Files, change.Path)
+	}
+
+	// In push mode, copy again so ignored files are also cleaned out of the store. In
+	// reverse sync mode this would delete ignored local files that were never backed up,
+	// and it would copy the excluded files.
+	if !plan.IsEmpty() && !reverse && len(exclude) == 0 {
+		if err := copyToStore(localSide, storePath, ignores); err != nil {
+			return result, err
+		}
+	}
+	if result.storeChanged && commit {
+		if err := app.GitCommitChanges("Update", name); err != nil {
+			return result, fmt.Errorf("error committing changes: %w", err)
+		}
+		result.Commit = app.headCommit(name)
+	}
+
+	if err := app.recordSyncExcluding(name, localPath, storePath, exclude); err != nil {
+		return result, fmt.Errorf("error updating manifest: %w", err)
+	}
+
+	if result.Changed() {
+		hc := app.SyncHookContext(name, reverse)
+		hc.ChangedFiles = result.Files
+		if err := app.RunHooks(config.HookPostSync, hc); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// PlanOneSided returns the plan copying the files of a dotfile changed since its last sync on
+// the destination side only back to the origin side, so that a sync does not revert them.
+// It is empty before the first sync, or when the destination is missing. The cleanup
+// function removes the staged copy of a templated or encrypted dotfile.
+func (app *App) PlanOneSided(name, localPath, storePath string, ignores []string, reverse bool) (*system.Plan, func(), error) {
+	noop := func() {}
+	manifest, err := app.Manifest()
+	if err != nil {
+		return nil, noop, fmt.Errorf("error loading manifest: %w", err)
+	}
+	last, ok := manifest.LastSync(name)
+	if !ok {
+		return &system.Plan{}, noop, nil
+	}
+
+	// A missing destination is a fresh start rather than a deletion of every file
+	destination := storePath
+	if reverse {
+		destination = localPath
+	}
+	if _, err := os.Stat(destination); os.IsNotExist(err) {
+		return &system.Plan{}, noop, nil
+	}
+
+	localChanges, storeChanges, err := system.ChangesSince(localPath, storePath, last, ignores, manifest)
+	if err != nil {
+		return nil, noop, fmt.Errorf("error comparing with the last sync: %w", err)
+	}
+	originChanges, destinationChanges := localChanges, storeChanges
+	if reverse {
+		originChanges, destinationChanges = storeChanges, localChanges
+	}
+	changedOnOrigin := make(map[string]bool, len(originChanges))
+	for _, path := range originChanges {
+		changedOnOrigin[path] = true
+	}
+	var paths []string
+	for _, path := range destinationChanges {
+		if !changedOnOrigin[path] {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		return &system.Plan{}, noop, nil
+	}
+
+	// The destination is the origin of these changes, staged when templated or encrypted
+	localSide, storeSide, cleanup, err := app.SyncSides(name, localPath, storePath, !reverse)
+	if err != nil {
+		return nil, noop, fmt.Errorf("error preparing '%s': %w", name, err)
+	}
+	from, to := storeSide, localSide
+	if reverse {
+		from, to = localSide, storeSide
+	}
+	plan, err := system.PlanFiles(from, to, paths)
+	if err != nil {
+		cleanup()
+		return nil, noop, fmt.Errorf("error comparing dotfiles: %w", err)
+	}
+	return plan, cleanup, nil
+}
+
+// syncOneSided applies the plan of PlanOneSided before a sync and returns the changed files
+// relative to the dotfile. Local files are backed up before they are overwritten.
+func (app *App) syncOneSided(result *SyncResult, localPath, storePath string, ignores, exclude []string) ([]system.TargetChange, error) {
+	plan, cleanup, err := app.PlanOneSided(result.Name, localPath, storePath, ignores, result.Reverse)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	if len(exclude) > 0 {
+		plan = plan.Excluding(exclude)
+	}
+	if plan.IsEmpty() {
+		return nil, nil
+	}
+
+	target := storePath
+	if !result.Reverse {
+		target = localPath
+		if result.Backup, err = app.BackupPlanTargets(result.Name, "sync", plan); err != nil {
+			return nil, fmt.Errorf("error backing up local files: %w", err)
+		}
+	}
+
+	if err := system.ApplyPlan(plan, ignores); err != nil {
+		return nil, fmt.Errorf("error copying dotfiles: %w", err)
+	}
+	for _, change := range plan.Changes {
+		if change.Type == system.ChangeDelete {
+			if err := system.RemoveEmptyParents(change.Target, target); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	result.storeChanged = result.Reverse
+	return plan.TargetChanges(target, ignores), nil
+}
+
+// copyToStore copies a local file or directory to its store entry
+func copyToStore(localPath, storePath string, ignores []string) error {
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return fmt.Errorf("error accessing source: %w", err)
+	}
+
+	if info.IsDir() {
+		return system.CopyDirectory(localPath, storePath, ignores)
+	}
+	if err := os.MkdirAll(filepath.Dir(storePath), 0755); err != nil {
+		return err
+	}
+	return system.CopyFile(localPath, storePath, ignores)
+}
@@ -0,0 +1,102 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bnema/gart/internal/config"
+	"github.com/bnema/gart/internal/git"
+	"github.com/bnema/gart/internal/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApp_SyncDotfile(t *testing.T) {
+	tempDir := t.TempDir()
+	storeDir := filepath.Join(tempDir, "store")
+	localDir := filepath.Join(tempDir, "home", ".config", "nvim")
+	require.NoError(t, os.MkdirAll(storeDir, 0755))
+	require.NoError(t, os.MkdirAll(localDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(localDir, "init.lua"), []byte("-- v1\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(localDir, "debug.log"), []byte("log\n"), 0644))
+
+	repo, err := git.NewRepository(storeDir)
+	require.NoError(t, err)
+	require.NoError(t, repo.Init("main"))
+
+	app := &App{
+		StoragePath: storeDir,
+		Config: &config.Config{
+			Dotfiles:        map[string]string{"nvim": localDir},
+			DotfilesIgnores: map[string][]string{"nvim": {"*.log"}},
+			Settings: config.SettingsConfig{
+				GitVersioning: true,
+				Git:           config.GitConfig{CommitMessageFormat: "{{ .Action }} {{ .Dotfile }}"},
+				Backup:        &config.BackupConfig{Enabled: false},
+			},
+		},
+		gitRepo: repo,
+	}
+
+	storePath, err := app.SyncStorePath("nvim", localDir)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(storeDir, "nvim"), storePath)
+
+	result, err := app.SyncDotfile("nvim", localDir, storePath, []string{"*.log"}, nil, false)
+	require.NoError(t, err)
+	assert.True(t, result.Changed())
+	assert.Equal(t, []string{"init.lua"}, result.Files)
+	assert.NoFileExists(t, filepath.Join(storePath, "debug.log"))
+
+	commits, err := repo.Log("nvim", 0)
+	require.NoError(t, err)
+	require.Len(t, commits, 1)
+	assert.Equal(t, "Update nvim", commits[0].Subject())
+
+	// Syncing again without local changes does nothing
+	result, err = app.SyncDotfile("nvim", localDir, storePath, []string{"*.log"}, nil, false)
+	require.NoError(t, err)
+	assert.False(t, result.Changed())
+}
+
+func TestApp_SyncDotfile_ExcludedFilesStayPending(t *testing.T) {
+	tempDir := t.TempDir()
+	storeDir := filepath.Join(tempDir, "store")
+	localDir := filepath.Join(tempDir, "home", ".config", "tool")
+	require.NoError(t, os.MkdirAll(storeDir, 0755))
+	require.NoError(t, os.MkdirAll(localDir, 0755))
+
+	name := "tool"
+	app := &App{
+		StoragePath: storeDir,
+		Config: &config.Config{
+			Dotfiles:        map[string]string{name: localDir},
+			DotfilesIgnores: map[string][]string{},
+			Settings:        config.SettingsConfig{Backup: &config.BackupConfig{Enabled: false}},
+		},
+	}
+	storePath := filepath.Join(storeDir, name)
+	for file, content := range map[string]string{"init.lua": "-- v1\n", "keys.lua": "-- v1\n"} {
+		require.NoError(t, os.WriteFile(filepath.Join(localDir, file), []byte(content), 0644))
+	}
+	_, err := app.SyncDotfile(name, localDir, storePath, nil, nil, false)
+	require.NoError(t, err)
+
+	// keys.lua is left out, e.g. because it has a security finding
+	for _, file := range []string{"init.lua", "keys.lua"} {
+		require.NoError(t, os.WriteFile(filepath.Join(localDir, file), []byte("-- v2\n"), 0644))
+	}
+	result, err := app.SyncDotfile(name, localDir, storePath, nil, []string{filepath.Join(localDir, "keys.lua")}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"init.lua"}, result.Files)
+
+	manifest, err := app.Manifest()
+	require.NoError(t, err)
+	last, ok := manifest.LastSync(name)
+	require.True(t, ok)
+	localChanges, storeChanges, err := system.ChangesSince(localDir, storePath, last, nil, manifest)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"keys.lua"}, localChanges, "the excluded change must still be pending")
+	assert.Empty(t, storeChanges)
+}
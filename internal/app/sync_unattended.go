@@ -0,0 +1,284 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/bnema/gart/internal/config"
+	"github.com/bnema/gart/internal/security"
+	"github.com/bnema/gart/internal/system"
+)
+
+// Statuses of a SyncReport
+const (
+	SyncStatusSynced    = "synced"
+	SyncStatusUnchanged = "unchanged"
+	SyncStatusSkipped   = "skipped"
+	SyncStatusFailed    = "failed"
+)
+
+// SyncReport is the outcome of a sync made without prompting
+type SyncReport struct {
+	SyncResult
+	LocalPath string
+	StorePath string
+	// Status is one of the SyncStatus constants
+	Status string
+	// Reason explains why the dotfile was skipped
+	Reason string
+	// Security is the report of the security scan, nil when no scan ran
+	Security *security.ScanReport
+	// SkippedFiles are the files with security findings left out of the sync
+	SkippedFiles []string
+	// Conflicts are the files changed both locally and in the store since the last sync
+	Conflicts []system.Conflict
+	// Err is set when the sync failed
+	Err error
+
+	// pendingCommit is set when the changes are left to the commit of the batch
+	pendingCommit bool
+}
+
+// ErrSyncAborted is returned by a SyncOptions callback to stop the sync of the remaining dotfiles
+var ErrSyncAborted = errors.New("sync aborted")
+
+// SyncOptions configures SyncUnattended and SyncBatch
+type SyncOptions struct {
+	Reverse      bool
+	SkipSecurity bool
+	// Policy answers security findings and conflicts the callbacks below do not handle
+	Policy system.PromptPolicy
+	// DecideSecurity returns whether to sync a dotfile with security findings and the files
+	// to leave out of it, the prompt policy decides when nil
+	DecideSecurity func(report *SyncReport, securityContext *security.SecurityContext) (bool, []string, error)
+	// ResolveConflicts returns how to resolve a dotfile changed on both sides when
+	// ConflictResolution is empty, an empty resolution skips the dotfile. The prompt
+	// policy decides when nil.
+	ResolveConflicts func(report *SyncReport) (string, error)
+
+	// deferCommit leaves committing the changes to the caller
+	deferCommit bool
+	// prompt serializes the callbacks of concurrent syncs
+	prompt *sync.Mutex
+}
+
+// ask runs a callback, one at a time when syncs run concurrently
+func (opts SyncOptions) ask(callback func()) {
+	if opts.prompt != nil {
+		opts.prompt.Lock()
+		defer opts.prompt.Unlock()
+	}
+	callback()
+}
+
+// SyncUnattended syncs a dotfile without prompting unless the options provide callbacks.
+// Security findings are handled by DecideSecurity or the prompt policy, conflicts by
+// ConflictResolution, ResolveConflicts or the policy. Symlinked dotfiles are scanned in
+// the store and committed.
+func (app *App) SyncUnattended(name string, opts SyncOptions) SyncReport {
+	reverse := opts.Reverse
+	report := SyncReport{SyncResult: SyncResult{Name: name, Reverse: reverse}}
+	fail := func(err error) SyncReport {
+		report.Status = SyncStatusFailed
+		report.Err = err
+		return report
+	}
+	skip := func(reason string) SyncReport {
+		report.Status = SyncStatusSkipped
+		report.Reason = reason
+		return report
+	}
+
+	path, ok := app.Config.Dotfiles[name]
+	if !ok {
+		return fail(fmt.Errorf("dotfile '%s' not found", name))
+	}
+	report.LocalPath = app.ExpandHomeDir(path)
+	ignores := app.Config.DotfilesIgnores[name]
+
+	if err := app.RunHooks(config.HookPreSync, app.SyncHookContext(name, reverse)); err != nil {
+		return fail(err)
+	}
+
+	symlink := app.IsSymlinkMode(name)
+	if symlink {
+		report.StorePath = app.StorePathFor(name, report.LocalPath)
+		if err := app.LinkDotfile(name); err != nil {
+			return fail(fmt.Errorf("symlink check failed: %w", err))
+		}
+	} else {
+		storePath, err := app.SyncStorePath(name, report.LocalPath)
+		if err != nil {
+			return fail(err)
+		}
+		report.StorePath = storePath
+	}
+
+	// Symlinked dotfiles are edited in the store, encrypted ones never reach it in plain text
+	securityConfig := app.Config.Settings.Security
+	if !opts.SkipSecurity && securityConfig != nil && securityConfig.Enabled && !app.IsEncrypted(name) {
+		scanPath := report.LocalPath
+		if symlink {
+			scanPath = report.StorePath
+		}
+
+		securityContext, err := app.NewSecurityContext(securityConfig)
+		if err != nil {
+			return fail(err)
+		}
+		scan, err := securityContext.ScanPath(scanPath, ignores)
+		if err != nil {
+			return fail(fmt.Errorf("security scan failed: %w", err))
+		}
+		report.Security = scan
+
+		var proceed bool
+		var skipFiles []string
+		if scan.TotalFindings > 0 && opts.DecideSecurity != nil {
+			opts.ask(func() { proceed, skipFiles, err = opts.DecideSecurity(&report, securityContext) })
+		} else {
+			proceed, skipFiles, err = securityContext.PolicyDecision(scan, opts.Policy)
+		}
+		if err != nil {
+			return fail(err)
+		}
+		if !proceed {
+			return fail(fmt.Errorf("sync aborted due to security concerns"))
+		}
+		report.SkippedFiles = skipFiles
+	}
+
+	if symlink {
+		// The files already live in the store, only the whole dotfile can be left out
+		if len(report.SkippedFiles) > 0 {
+			return skip(fmt.Sprintf("%d file(s) with security findings", len(report.SkippedFiles)))
+		}
+		var changed bool
+		var err error
+		opts.ask(func() {
+			// Ignores may have been edited in the config file since the dotfile was linked
+			if err = app.UpdateStoreGitignore(); err == nil {
+				changed, err = app.storeChanged(name)
+			}
+		})
+		if err != nil {
+			return fail(fmt.Errorf("error checking the store: %w", err))
+		}
+		if !changed {
+			report.Status = SyncStatusUnchanged
+			return report
+		}
+		if opts.deferCommit {
+			report.pendingCommit = true
+		} else if err := app.GitCommitChanges("Update", name); err != nil {
+			return fail(fmt.Errorf("error committing changes: %w", err))
+		}
+		report.Status = SyncStatusSynced
+		return report
+	}
+
+	conflicts, err := app.DetectConflicts(name, report.LocalPath, report.StorePath)
+	if err != nil {
+		return fail(fmt.Errorf("error checking for conflicts: %w", err))
+	}
+	if len(conflicts) > 0 {
+		report.Conflicts = conflicts
+
+		var failure error
+		var skipReason string
+		opts.ask(func() {
+			resolution := app.ConflictResolution
+			if resolution == "" && opts.ResolveConflicts != nil {
+				if resolution, failure = opts.ResolveConflicts(&report); failure != nil {
+					return
+				}
+				if resolution == "" {
+					skipReason = "changed both locally and in the store since the last sync"
+					return
+				}
+			}
+
+			switch resolution {
+			case ResolveLocal:
+				// Markers written to resolve later must not reach the store
+				if failure = CheckMarkersResolved(conflicts); failure != nil {
+					return
+				}
+				reverse = false
+			case ResolveStore:
+				reverse = true
+			case ResolveMerge:
+				// Opens the editor, which is why it runs with the callbacks
+				if err := app.MergeConflicts(name, report.LocalPath, report.StorePath, conflicts); err != nil {
+					failure = fmt.Errorf("merge failed: %w", err)
+					return
+				}
+				reverse = false
+			case ResolveMarkers:
+				backup, err := app.WriteConflictMarkers(name, report.LocalPath, report.StorePath, conflicts)
+				if err != nil {
+					failure = fmt.Errorf("error writing conflict markers: %w", err)
+					return
+				}
+				skipReason = fmt.Sprintf("conflict markers written, resolve them and run 'gart sync %s --resolve %s'", name, ResolveLocal)
+				if backup != nil {
+					report.Backup = backup
+					skipReason = fmt.Sprintf("conflict markers written, local versions saved in backup '%s', resolve them and run 'gart sync %s --resolve %s'", backup.ID, name, ResolveLocal)
+				}
+			default:
+				if opts.Policy == system.PromptFail {
+					failure = fmt.Errorf("changed both locally and in the store since the last sync: %w", system.ErrInputRequired)
+					return
+				}
+				skipReason = fmt.Sprintf("changed both locally and in the store since the last sync, run 'gart sync %s --resolve <local|store|merge|markers>'", name)
+			}
+		})
+		if failure != nil {
+			return fail(failure)
+		}
+		if skipReason != "" {
+			return skip(skipReason)
+		}
+	}
+
+	result, err := app.syncDotfile(name, report.LocalPath, report.StorePath, ignores, report.SkippedFiles, reverse, !opts.deferCommit)
+	report.SyncResult = result
+	if err != nil {
+		return fail(err)
+	}
+
+	report.Status = SyncStatusUnchanged
+	if result.Changed() {
+		report.Status = SyncStatusSynced
+		report.pendingCommit = opts.deferCommit && result.storeChanged
+	}
+	return report
+}
+
+// storeChanged reports whether the store entry of a dotfile has uncommitted changes. It is
+// always true without git versioning, nothing tells the changes apart then.
+func (app *App) storeChanged(name string) (bool, error) {
+	if !app.Config.Settings.GitVersioning {
+		return true, nil
+	}
+	rel, err := app.storeRelPath(name)
+	if err != nil {
+		return false, err
+	}
+	repo, err := app.getOrCreateGitRepository()
+	if err != nil {
+		return false, err
+	}
+	files, err := repo.Status()
+	if err != nil {
+		return false, err
+	}
+	for _, file := range files {
+		if file == rel || strings.HasPrefix(file, rel+"/") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,104 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bnema/gart/internal/config"
+	"github.com/bnema/gart/internal/system"
+)
+
+// IsTemplate reports whether the store content of a dotfile is rendered as a template
+func (app *App) IsTemplate(name string) bool {
+	return app.Config.DotfilesTemplates[name]
+}
+
+// SetDotfileTemplate enables or disables template rendering for a dotfile
+func (app *App) SetDotfileTemplate(name string, enabled bool) error {
+	if app.Config.DotfilesTemplates == nil {
+		app.Config.DotfilesTemplates = make(map[string]bool)
+	}
+	if enabled {
+		app.Config.DotfilesTemplates[name] = true
+	} else {
+		delete(app.Config.DotfilesTemplates, name)
+	}
+
+	return config.UpdateDotfileTemplate(app.ConfigFilePath, name, enabled)
+}
+
+// TemplateData returns the variables available to dotfile templates on this machine
+func (app *App) TemplateData() system.TemplateData {
+	return system.NewTemplateData(app.ActiveProfile, app.Config.Settings.TemplateData)
+}
+
+// SyncSides returns the local and store paths to compare when syncing a dotfile.
+// For templated or encrypted dotfiles one side is replaced by a staged copy: the
+// decrypted and rendered store content when the store is the origin, or the local
+// content turned back into templates and encrypted when the local path is the
+// origin. The returned cleanup function removes the staged copy.
+func (app *App) SyncSides(name, localPath, storePath string, storeIsOrigin bool) (string, string, func(), error) {
+	noop := func() {}
+	templated, encrypted := app.IsTemplate(name), app.IsEncrypted(name)
+	if !templated && !encrypted {
+		return localPath, storePath, noop, nil
+	}
+
+	var enc *system.Encrypter
+	if encrypted {
+		var err error
+		if enc, err = app.Encrypter(); err != nil {
+			return "", "", noop, fmt.Errorf("failed to load encryption key: %w", err)
+		}
+	}
+
+	stageDir, err := os.MkdirTemp("", "gart-stage-*")
+	if err != nil {
+		return "", "", noop, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	cleanup := func() { _ = os.RemoveAll(stageDir) }
+	fail := func(err error) (string, string, func(), error) {
+		cleanup()
+		return "", "", noop, err
+	}
+	stage := func(step string) string {
+		return filepath.Join(stageDir, step, filepath.Base(storePath))
+	}
+
+	// Plain store content, a store entry that does not exist yet stays as is
+	plainStore := storePath
+	if _, err := os.Stat(storePath); encrypted && err == nil {
+		plainStore = stage("decrypted")
+		if err := system.DecryptTree(storePath, plainStore, enc); err != nil {
+			return fail(fmt.Errorf("failed to decrypt '%s': %w", name, err))
+		}
+	}
+
+	if storeIsOrigin {
+		storeSide := plainStore
+		if templated {
+			storeSide = stage("rendered")
+			if err := system.RenderTree(plainStore, storeSide, app.TemplateData()); err != nil {
+				return fail(fmt.Errorf("failed to render templates of '%s': %w", name, err))
+			}
+		}
+		return localPath, storeSide, cleanup, nil
+	}
+
+	localSide := localPath
+	if templated {
+		localSide = stage("reversed")
+		if err := system.ReverseTree(localPath, plainStore, localSide, app.TemplateData()); err != nil {
+			return fail(fmt.Errorf("failed to update templates of '%s': %w", name, err))
+		}
+	}
+	if encrypted {
+		plainLocal := localSide
+		localSide = stage("encrypted")
+		if err := system.EncryptTree(plainLocal, storePath, localSide, enc); err != nil {
+			return fail(fmt.Errorf("failed to encrypt '%s': %w", name, err))
+		}
+	}
+	return localSide, storePath, cleanup, nil
+}
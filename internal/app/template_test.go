@@ -0,0 +1,51 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bnema/gart/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApp_TemplatedDotfile(t *testing.T) {
+	tempDir := t.TempDir()
+	storeDir := filepath.Join(tempDir, "store")
+	localFile := filepath.Join(tempDir, "home", ".gitconfig")
+	storeFile := filepath.Join(storeDir, ".gitconfig")
+
+	require.NoError(t, os.MkdirAll(storeDir, 0755))
+	require.NoError(t, os.WriteFile(storeFile, []byte("[user]\n\temail = {{ .Data.email }}\n[core]\n\teditor = vim\n"), 0644))
+
+	app := &App{
+		StoragePath: storeDir,
+		Config: &config.Config{
+			Settings: config.SettingsConfig{
+				TemplateData: map[string]string{"email": "me@work.example"},
+				Backup:       &config.BackupConfig{Enabled: false},
+			},
+			Dotfiles:          map[string]string{"git": localFile},
+			DotfilesTemplates: map[string]bool{"git": true},
+		},
+	}
+
+	// Deploy renders the template
+	result := app.DeployDotfile("git")
+	require.NoError(t, result.Err)
+	content, err := os.ReadFile(localFile)
+	require.NoError(t, err)
+	assert.Equal(t, "[user]\n\temail = me@work.example\n[core]\n\teditor = vim\n", string(content))
+
+	// A local edit of a literal line is turned back into the template on push
+	require.NoError(t, os.WriteFile(localFile, []byte("[user]\n\temail = me@work.example\n[core]\n\teditor = nvim\n"), 0644))
+	localSide, storeSide, cleanup, err := app.SyncSides("git", localFile, storeFile, false)
+	require.NoError(t, err)
+	defer cleanup()
+	assert.Equal(t, storeFile, storeSide)
+
+	content, err = os.ReadFile(localSide)
+	require.NoError(t, err)
+	assert.Equal(t, "[user]\n\temail = {{ .Data.email }}\n[core]\n\teditor = nvim\n", string(content))
+}
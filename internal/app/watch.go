@@ -0,0 +1,89 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bnema/gart/internal/system"
+)
+
+// DefaultWatchDebounce is how long the watcher waits for a burst of writes to settle
+const DefaultWatchDebounce = 2 * time.Second
+
+// Watch syncs the dotfiles to the store whenever their files change, until ctx is done.
+// Dotfiles with blocking security findings or conflicting changes are skipped and logged.
+func (app *App) Watch(ctx context.Context, debounce time.Duration, logger *log.Logger) error {
+	watcher, err := system.NewWatcher(debounce)
+	if err != nil {
+		return fmt.Errorf("failed to start watcher: %w", err)
+	}
+	defer func() { _ = watcher.Close() }()
+
+	names := make([]string, 0, len(app.Config.Dotfiles))
+	for name := range app.Config.Dotfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	watched := 0
+	for _, name := range names {
+		path := app.ExpandHomeDir(app.Config.Dotfiles[name])
+		// Symlinked dotfiles are edited in the store through the link
+		if app.IsSymlinkMode(name) {
+			path = app.StorePathFor(name, path)
+		}
+
+		target := system.WatchTarget{Name: name, Path: path, Ignores: app.Config.DotfilesIgnores[name]}
+		if err := watcher.Add(target); err != nil {
+			logger.Printf("not watching '%s': %v", name, err)
+			continue
+		}
+		watched++
+	}
+	if watched == 0 {
+		return fmt.Errorf("no dotfile to watch")
+	}
+	logger.Printf("watching %d dotfile(s)", watched)
+
+	return watcher.Run(ctx, func(changed []string) {
+		for _, name := range changed {
+			app.watchSync(name, logger)
+		}
+	}, func(err error) {
+		logger.Printf("watch error: %v", err)
+	})
+}
+
+// watchSync pushes a changed dotfile to the store without prompting. Security findings are
+// handled by the prompt policy, which defaults to proceed, and conflicts are left for sync.
+func (app *App) watchSync(name string, logger *log.Logger) {
+	policy := app.PromptPolicy
+	if policy == "" {
+		policy = system.PromptProceed
+	}
+
+	report := app.SyncUnattended(name, SyncOptions{Policy: policy})
+	if report.Security != nil && report.Security.TotalFindings > 0 && report.Status != SyncStatusFailed {
+		logger.Printf("'%s' has %d security finding(s) with %s risk", name, report.Security.TotalFindings, report.Security.HighestRisk)
+	}
+
+	switch report.Status {
+	case SyncStatusFailed:
+		logger.Printf("error syncing '%s': %v", name, report.Err)
+	case SyncStatusSkipped:
+		logger.Printf("skipping '%s': %s", name, report.Reason)
+	case SyncStatusSynced:
+		if len(report.SkippedFiles) > 0 {
+			logger.Printf("left out of '%s': %s", name, strings.Join(report.SkippedFiles, ", "))
+		}
+		if report.Changed() {
+			logger.Printf("synced '%s': %s", name, strings.Join(report.Files, ", "))
+		} else {
+			logger.Printf("committed symlinked '%s'", name)
+		}
+	}
+}
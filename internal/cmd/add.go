@@ -6,19 +6,24 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/bnema/gart/internal/config"
 	"github.com/bnema/gart/internal/ui"
 	"github.com/spf13/cobra"
 )
 
 func getAddCmd() *cobra.Command {
 	var ignores []string
+	var symlink bool
+	var dryRun bool
+	var template bool
+	var encrypt bool
 	cmd := &cobra.Command{
 		Use:   "add [path] [name]",
 		Short: "Add a new dotfile or folder",
 		Run: func(cmd *cobra.Command, args []string) {
 			if len(args) == 0 {
-				fmt.Println("Invalid arguments. Usage: add [path] opt:[name]")
-				return
+				fmt.Fprintln(os.Stderr, "Invalid arguments. Usage: add [path] opt:[name]")
+				os.Exit(1)
 			}
 
 			path := args[0]
@@ -29,14 +34,34 @@ func getAddCmd() *cobra.Command {
 			} else if len(args) == 2 {
 				name = args[1]
 			} else {
-				fmt.Println("Invalid arguments. Usage: add [path] opt:[name]")
-				return
+				fmt.Fprintln(os.Stderr, "Invalid arguments. Usage: add [path] opt:[name]")
+				os.Exit(1)
 			}
 
-			ui.RunAddDotfileView(appInstance, path, name, ignores)
+			if symlink && encrypt {
+				fmt.Fprintln(os.Stderr, "--symlink and --encrypt cannot be combined: a symlink exposes the store content as is")
+				os.Exit(1)
+			}
+			if symlink && template {
+				fmt.Fprintln(os.Stderr, "--symlink and --template cannot be combined: a symlink exposes the store content unrendered")
+				os.Exit(1)
+			}
+			// Encrypted and templated dotfiles are always copied, whatever the default mode
+			symlink = !encrypt && !template && (symlink || appInstance.Config.Settings.Mode == config.ModeSymlink)
+			if dryRun {
+				ui.RunAddPlanView(appInstance, path, name, ignores, symlink)
+				return
+			}
+			if !ui.RunAddDotfileView(appInstance, path, name, ignores, symlink, template, encrypt) {
+				os.Exit(1)
+			}
 		},
 	}
 	cmd.Flags().StringSliceVar(&ignores, "ignore", []string{}, "Paths to ignore (can be used multiple times)")
+	cmd.Flags().BoolVar(&symlink, "symlink", false, "Move the dotfile into the store and replace it with a symlink")
+	cmd.Flags().BoolVar(&template, "template", false, "Render the stored files as Go templates on deploy and reverse sync")
+	cmd.Flags().BoolVar(&encrypt, "encrypt", false, "Encrypt the stored files with age, they are decrypted on deploy and reverse sync")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the planned file operations without changing anything")
 	return cmd
 }
 
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/bnema/gart/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func getBackupsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backups",
+		Short: "Manage backups of local files taken before they were overwritten",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List all backups",
+		Run: func(cmd *cobra.Command, args []string) {
+			if !ui.RunBackupListView(appInstance) {
+				os.Exit(1)
+			}
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "restore [id]",
+		Short: "Restore the files of a backup to their original locations",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if !ui.RunBackupRestoreView(appInstance, args[0]) {
+				os.Exit(1)
+			}
+		},
+	})
+
+	return cmd
+}
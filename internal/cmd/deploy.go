@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/bnema/gart/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func getDeployCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "deploy [name...]",
+		Short: "Restore dotfiles from the store to their local paths",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := appInstance.AutoPull(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if !ui.RunDeployView(appInstance, args) {
+				os.Exit(1)
+			}
+		},
+	}
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/bnema/gart/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func getDiffCmd() *cobra.Command {
+	var stat bool
+
+	cmd := &cobra.Command{
+		Use:         "diff [name...]",
+		Short:       "Show the pending changes between local dotfiles and the store",
+		Annotations: map[string]string{jsonOutputAnnotation: "true"},
+		Run: func(cmd *cobra.Command, args []string) {
+			if appInstance.JSONOutput {
+				if !ui.RunDiffJSON(appInstance, args, stat) {
+					os.Exit(1)
+				}
+				return
+			}
+			if !ui.RunDiffView(appInstance, args, stat) {
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&stat, "stat", false, "Only print a summary of the changed files")
+
+	return cmd
+}
@@ -1,16 +1,31 @@
 package cmd
 
 import (
+	"os"
+
 	"github.com/bnema/gart/internal/ui"
 	"github.com/spf13/cobra"
 )
 
 func getListCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "list",
-		Short: "List all dotfiles",
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:         "list",
+		Short:       "List all dotfiles",
+		Annotations: map[string]string{jsonOutputAnnotation: "true"},
 		Run: func(cmd *cobra.Command, args []string) {
-			ui.RunListView(appInstance)
+			if appInstance.JSONOutput {
+				if !ui.RunListJSON(appInstance) {
+					os.Exit(1)
+				}
+				return
+			}
+			ui.RunListView(appInstance, dryRun)
 		},
 	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what removing a dotfile would do without changing anything")
+
+	return cmd
 }
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/bnema/gart/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func getLogCmd() *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "log [name]",
+		Short: "List the commits that changed a dotfile, or the whole store",
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			name := ""
+			if len(args) == 1 {
+				name = args[0]
+			}
+			if !ui.RunLogView(appInstance, name, limit) {
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().IntVarP(&limit, "limit", "n", 0, "Maximum number of commits to list (0 = all)")
+
+	return cmd
+}
+
+func getShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <rev> [name]",
+		Short: "Show the changes a commit made to a dotfile, or to the whole store",
+		Args:  cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			name := ""
+			if len(args) == 2 {
+				name = args[1]
+			}
+			if !ui.RunShowView(appInstance, args[0], name) {
+				os.Exit(1)
+			}
+		},
+	}
+}
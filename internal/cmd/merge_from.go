@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/bnema/gart/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func getMergeFromCmd() *cobra.Command {
+	var all bool
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "merge-from <host> [name...]",
+		Short: "Adopt dotfiles from another machine's branch of the store",
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if !ui.RunMergeFromView(appInstance, args[0], args[1:], all, dryRun) {
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "Adopt every differing dotfile without prompting")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only list the dotfiles and files that differ")
+
+	return cmd
+}
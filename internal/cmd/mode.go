@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/bnema/gart/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func getModeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "mode [name] [copy|symlink]",
+		Short: "Switch a dotfile between copy and symlink mode",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			name, mode := args[0], args[1]
+
+			var err error
+			switch mode {
+			case config.ModeCopy:
+				err = appInstance.UnlinkDotfile(name)
+			case config.ModeSymlink:
+				err = appInstance.ConvertToSymlink(name)
+			default:
+				err = config.ValidateMode(mode)
+			}
+
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error switching '%s' to %s mode: %v\n", name, mode, err)
+				os.Exit(1)
+			}
+
+			fmt.Printf("Dotfile '%s' is now in %s mode.\n", name, mode)
+		},
+	}
+}
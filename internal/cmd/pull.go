@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func getPullCmd() *cobra.Command {
+	var branch string
+	var strategy string
+
+	cmd := &cobra.Command{
+		Use:   "pull",
+		Short: "Fetch the remote of the store and integrate its changes",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := appInstance.GitPull(branch, strategy); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println("Store is up to date with the remote.")
+		},
+	}
+
+	cmd.Flags().StringVar(&branch, "branch", "", "Remote branch to integrate (default: the current branch)")
+	cmd.Flags().StringVar(&strategy, "strategy", "", "How to integrate diverged branches: ff-only, merge or rebase (default: pull_strategy setting or ff-only)")
+
+	return cmd
+}
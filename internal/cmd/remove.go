@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/bnema/gart/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func getRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Stop managing a dotfile and delete it from the store",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if !ui.RunRemoveView(appInstance, args[0]) {
+				os.Exit(1)
+			}
+		},
+	}
+}
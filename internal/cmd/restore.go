@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/bnema/gart/internal/app"
+	"github.com/bnema/gart/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func getRestoreCmd() *cobra.Command {
+	var rev string
+	var atValue string
+	var deploy bool
+
+	cmd := &cobra.Command{
+		Use:   "restore <name>",
+		Short: "Roll a dotfile back to an earlier version of the store",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if rev == "" && atValue == "" {
+				fmt.Fprintln(os.Stderr, "Error: one of --rev or --at is required")
+				os.Exit(1)
+			}
+
+			var at time.Time
+			if atValue != "" {
+				var err error
+				if at, err = app.ParseRestoreTime(atValue); err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+			}
+
+			if !ui.RunRestoreView(appInstance, args[0], rev, at, deploy) {
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&rev, "rev", "", "Revision to restore, e.g. a commit hash or HEAD~3")
+	cmd.Flags().StringVar(&atValue, "at", "", "Restore the version stored at this time, e.g. 2026-09-01 or '2026-09-01 18:30'")
+	cmd.Flags().BoolVar(&deploy, "deploy", false, "Also deploy the restored version to the local path")
+	cmd.MarkFlagsMutuallyExclusive("rev", "at")
+
+	return cmd
+}
@@ -5,20 +5,59 @@ import (
 	"os"
 
 	"github.com/bnema/gart/internal/app"
+	"github.com/bnema/gart/internal/system"
 	"github.com/spf13/cobra"
 )
 
 var (
 	rootCmd     *cobra.Command
 	appInstance *app.App
+	profileName string
+
+	nonInteractive bool
+	assumeYes      bool
+	promptPolicy   string
+	outputFormat   string
 )
 
+// jsonOutputAnnotation marks the commands supporting --output json
+const jsonOutputAnnotation = "json_output"
+
 func init() {
 	rootCmd = &cobra.Command{
 		Use:   "gart",
 		Short: "Gart is a dotfile manager",
 		Long:  `Gart is a command-line tool for managing dotfiles.`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			policy := promptPolicy
+			if assumeYes {
+				policy = string(system.PromptProceed)
+			}
+			switch outputFormat {
+			case "", "text":
+			case "json":
+				if cmd.Annotations[jsonOutputAnnotation] == "" {
+					return fmt.Errorf("'%s' does not support --output json", cmd.CommandPath())
+				}
+				appInstance.JSONOutput = true
+			default:
+				return fmt.Errorf("invalid output format '%s': must be one of: text, json", outputFormat)
+			}
+
+			// Prompts would be mixed with the JSON document
+			if err := appInstance.ConfigurePrompts(nonInteractive || appInstance.JSONOutput, policy); err != nil {
+				return err
+			}
+			return appInstance.ActivateProfile(profileName)
+		},
 	}
+
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "Profile selecting the active dotfiles (default: matched by hostname)")
+	rootCmd.PersistentFlags().BoolVar(&nonInteractive, "non-interactive", false, "Never prompt, answer prompts with the prompt policy")
+	rootCmd.PersistentFlags().BoolVarP(&assumeYes, "yes", "y", false, "Never prompt and proceed, same as --prompt-policy proceed")
+	rootCmd.PersistentFlags().StringVar(&promptPolicy, "prompt-policy", "", "Answer to prompts in non-interactive mode: fail, skip or proceed (default: prompt_policy setting or fail)")
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "output", "text", "Output format: text or json (list, sync, diff, status and scan)")
+	rootCmd.MarkFlagsMutuallyExclusive("yes", "prompt-policy")
 }
 
 func Execute(a *app.App) {
@@ -27,8 +66,21 @@ func Execute(a *app.App) {
 	rootCmd.AddCommand(getVersionCmd())
 	rootCmd.AddCommand(getAddCmd())
 	rootCmd.AddCommand(getSyncCmd())
+	rootCmd.AddCommand(getDeployCmd())
+	rootCmd.AddCommand(getPullCmd())
+	rootCmd.AddCommand(getLogCmd())
+	rootCmd.AddCommand(getShowCmd())
+	rootCmd.AddCommand(getRestoreCmd())
+	rootCmd.AddCommand(getMergeFromCmd())
+	rootCmd.AddCommand(getWatchCmd())
+	rootCmd.AddCommand(getDiffCmd())
+	rootCmd.AddCommand(getStatusCmd())
+	rootCmd.AddCommand(getScanCmd())
+	rootCmd.AddCommand(getBackupsCmd())
 	rootCmd.AddCommand(getListCmd())
+	rootCmd.AddCommand(getRemoveCmd())
 	rootCmd.AddCommand(getEditCmd())
+	rootCmd.AddCommand(getModeCmd())
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/bnema/gart/internal/security"
+	"github.com/bnema/gart/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func getScanCmd() *cobra.Command {
+	var store, updateBaseline bool
+	var format, reportPath, failOn, testRule string
+
+	cmd := &cobra.Command{
+		Use:   "scan [name|path...]",
+		Short: "Scan dotfiles, paths or the store for secrets",
+		Long: `Scan dotfiles, paths or the store for secrets without syncing anything.
+
+Without arguments every dotfile found on this host is scanned, except encrypted ones.
+Findings accepted with --update-baseline are no longer reported, by scan or by sync.
+--test-rule runs a rule of [[settings.security.rules]] alone, "-" reading from stdin, and
+exits with 1 when it reports nothing.
+Exits with 1 when the highest risk found reaches --fail-on and with 2 when the scan failed.`,
+		Annotations: map[string]string{jsonOutputAnnotation: "true"},
+		Run: func(cmd *cobra.Command, args []string) {
+			if testRule != "" {
+				if appInstance.JSONOutput {
+					fmt.Fprintln(os.Stderr, "Error: --test-rule is not available with --output json")
+					os.Exit(2)
+				}
+				matched, err := ui.RunTestRule(appInstance, testRule, args, store)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(2)
+				}
+				if !matched {
+					os.Exit(1)
+				}
+				return
+			}
+
+			if updateBaseline {
+				if appInstance.JSONOutput {
+					fmt.Fprintln(os.Stderr, "Error: --update-baseline is not available with --output json")
+					os.Exit(2)
+				}
+				if !ui.RunUpdateBaseline(appInstance, args, store) {
+					os.Exit(2)
+				}
+				return
+			}
+
+			if appInstance.JSONOutput {
+				if cmd.Flags().Changed("format") && format != "json" {
+					fmt.Fprintf(os.Stderr, "Error: --output json conflicts with --format %s\n", format)
+					os.Exit(2)
+				}
+				format = "json"
+			}
+			switch format {
+			case "text", "json", "sarif":
+			default:
+				fmt.Fprintf(os.Stderr, "Error: invalid report format '%s': must be one of: text, json, sarif\n", format)
+				os.Exit(2)
+			}
+
+			level, err := security.ParseRiskLevel(failOn)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(2)
+			}
+
+			passed, err := ui.RunScan(appInstance, args, store, format, reportPath, level)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(2)
+			}
+			if !passed {
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&store, "store", false, "Scan the whole store")
+	cmd.Flags().StringVar(&format, "format", "text", "Report format: text, json or sarif")
+	cmd.Flags().StringVar(&reportPath, "report", "", "Write the report to this file and print the text report")
+	cmd.Flags().StringVar(&failOn, "fail-on", "high", "Exit with 1 when a finding reaches this risk: low, medium, high, critical or none")
+
+	cmd.Flags().BoolVar(&updateBaseline, "update-baseline", false, "Accept the current findings of the scanned files in the baseline")
+	cmd.MarkFlagsMutuallyExclusive("update-baseline", "format")
+	cmd.MarkFlagsMutuallyExclusive("update-baseline", "report")
+	cmd.MarkFlagsMutuallyExclusive("update-baseline", "fail-on")
+
+	cmd.Flags().StringVar(&testRule, "test-rule", "", "Run only this user-defined rule and show every match")
+	cmd.MarkFlagsMutuallyExclusive("test-rule", "update-baseline")
+	cmd.MarkFlagsMutuallyExclusive("test-rule", "format")
+	cmd.MarkFlagsMutuallyExclusive("test-rule", "report")
+	cmd.MarkFlagsMutuallyExclusive("test-rule", "fail-on")
+
+	return cmd
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/bnema/gart/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func getStatusCmd() *cobra.Command {
+	var fetch bool
+
+	cmd := &cobra.Command{
+		Use:         "status [name...]",
+		Short:       "Show which dotfiles drifted since their last sync, without changing anything",
+		Annotations: map[string]string{jsonOutputAnnotation: "true"},
+		Run: func(cmd *cobra.Command, args []string) {
+			var ok bool
+			if appInstance.JSONOutput {
+				ok = ui.RunStatusJSON(appInstance, args, fetch)
+			} else {
+				ok = ui.RunStatusView(appInstance, args, fetch)
+			}
+			if !ok {
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&fetch, "fetch", false, "Fetch the remote first to count the commits to pull")
+
+	return cmd
+}
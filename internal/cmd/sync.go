@@ -2,32 +2,73 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
+	"github.com/bnema/gart/internal/app"
 	"github.com/bnema/gart/internal/ui"
 	"github.com/spf13/cobra"
 )
 
 func getSyncCmd() *cobra.Command {
 	var skipSecurity bool
-	
+	var dryRun bool
+	var showDiff bool
+	var resolve string
+	var jobs int
+
 	cmd := &cobra.Command{
-		Use:   "sync [name]",
-		Short: "Sync a dotfile or all dotfiles",
+		Use:         "sync [name]",
+		Short:       "Sync a dotfile or all dotfiles",
+		Annotations: map[string]string{jsonOutputAnnotation: "true"},
 		Run: func(cmd *cobra.Command, args []string) {
-			if len(args) == 0 {
-				syncAllDotfiles(skipSecurity)
+			if err := app.ValidateResolution(resolve); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			appInstance.ConflictResolution = resolve
+			if appInstance.JSONOutput && (dryRun || showDiff) {
+				fmt.Fprintln(os.Stderr, "Error: --dry-run and --diff cannot be used with --output json, use 'gart diff --output json' instead")
+				os.Exit(1)
+			}
+
+			// Receive the other machines' changes before the store overwrites local files
+			if appInstance.Config.Settings.ReverseSyncMode && !dryRun {
+				if err := appInstance.AutoPull(); err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+			}
+
+			var ok bool
+			if appInstance.JSONOutput {
+				ok = ui.RunSyncJSON(appInstance, args, skipSecurity, jobs)
+			} else if len(args) == 0 {
+				ok = syncAllDotfiles(skipSecurity, dryRun, showDiff, jobs)
 			} else {
-				syncSingleDotfile(args[0], skipSecurity)
+				ok = syncSingleDotfile(args[0], skipSecurity, dryRun, showDiff)
+			}
+			if !ok {
+				os.Exit(1)
 			}
 		},
 	}
 	
 	cmd.Flags().BoolVar(&skipSecurity, "no-security", false, "Skip security scanning")
-	
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the planned file operations without changing anything")
+	cmd.Flags().BoolVar(&showDiff, "diff", false, "Show the diff of each dotfile before syncing it")
+	cmd.Flags().IntVarP(&jobs, "jobs", "j", 0, "Number of dotfiles synced concurrently (default: sync_jobs setting or the number of CPUs)")
+	cmd.Flags().StringVar(&resolve, "resolve", "", "Resolve dotfiles changed on both sides without prompting: local, store, merge or markers")
+
 	return cmd
 }
 
-func syncAllDotfiles(skipSecurity bool) {
+// syncAllDotfiles syncs every dotfile and returns false if a sync failed or was aborted.
+// Dotfiles are synced concurrently unless their plan or diff is printed first.
+func syncAllDotfiles(skipSecurity, dryRun, showDiff bool, jobs int) bool {
+	if !dryRun && !showDiff {
+		return ui.RunSyncBatchView(appInstance, skipSecurity, jobs)
+	}
+
 	skipAllSecurity := false // Track skip all flag across iterations
 	
 	for name, path := range appInstance.Config.Dotfiles {
@@ -35,23 +76,38 @@ func syncAllDotfiles(skipSecurity bool) {
 		appInstance.Dotfile.Path = path
 		// Get ignores for this dotfile
 		ignores := appInstance.Config.DotfilesIgnores[name]
-		if !ui.RunSyncView(appInstance, ignores, skipSecurity, &skipAllSecurity) {
+		if showDiff {
+			ui.RunDiffView(appInstance, []string{name}, false)
+		}
+		if dryRun {
+			ui.RunSyncPlanView(appInstance, ignores)
+			continue
+		}
+		if !ui.RunSyncView(appInstance, name, skipSecurity, &skipAllSecurity) {
 			// User aborted sync, stop processing remaining dotfiles
-			break
+			return false
 		}
 	}
+	return true
 }
 
-func syncSingleDotfile(name string, skipSecurity bool) {
+// syncSingleDotfile syncs one dotfile and returns false if the sync failed or was aborted
+func syncSingleDotfile(name string, skipSecurity, dryRun, showDiff bool) bool {
 	path, ok := appInstance.Config.Dotfiles[name]
 	if !ok {
 		fmt.Printf("Dotfile '%s' not found.\n", name)
-		return
+		return false
+	}
+
+	if showDiff {
+		ui.RunDiffView(appInstance, []string{name}, false)
 	}
-	appInstance.Dotfile.Name = name
-	appInstance.Dotfile.Path = path
-	// Get ignores for this dotfile
-	ignores := appInstance.Config.DotfilesIgnores[name]
-	// For single dotfile, pass nil for skipAllSecurity since there's no batch
-	ui.RunSyncView(appInstance, ignores, skipSecurity, nil)
+
+	if dryRun {
+		appInstance.Dotfile.Name = name
+		appInstance.Dotfile.Path = path
+		return ui.RunSyncPlanView(appInstance, appInstance.Config.DotfilesIgnores[name])
+	}
+
+	return ui.RunSyncView(appInstance, name, skipSecurity, nil)
 }
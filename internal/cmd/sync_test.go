@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bnema/gart/internal/app"
+	"github.com/bnema/gart/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncSingleDotfile_DryRun(t *testing.T) {
+	dotfileDir, storageDir, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	createTestFiles(t, dotfileDir, []string{"config.toml"}, nil)
+	storeFile := filepath.Join(storageDir, "testdotfile", "config.toml")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(storeFile), 0755))
+	assert.NoError(t, os.WriteFile(storeFile, []byte("stored content"), 0644))
+
+	previous := appInstance
+	defer func() { appInstance = previous }()
+	appInstance = &app.App{
+		StoragePath: storageDir,
+		Config: &config.Config{
+			Settings: config.SettingsConfig{StoragePath: storageDir},
+			Dotfiles: map[string]string{"testdotfile": dotfileDir},
+		},
+	}
+
+	syncSingleDotfile("testdotfile", true, true, false)
+
+	// A dry run only prints the plan
+	content, err := os.ReadFile(storeFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "stored content", string(content))
+	entries, err := os.ReadDir(filepath.Dir(storeFile))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/bnema/gart/internal/app"
+	"github.com/bnema/gart/internal/system"
+	"github.com/spf13/cobra"
+)
+
+// watchUnitName is the systemd user unit installed by watch --install-systemd
+const watchUnitName = "gart-watch.service"
+
+func getWatchCmd() *cobra.Command {
+	var debounce time.Duration
+	var logFile string
+	var installSystemd bool
+
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Sync dotfiles to the store automatically when they change",
+		Run: func(cmd *cobra.Command, args []string) {
+			if installSystemd {
+				if err := installWatchUnit(cmd); err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+				return
+			}
+
+			if logFile == "" {
+				dataDir, err := system.GetDataPaths()
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+				logFile = filepath.Join(dataDir, "watch.log")
+			}
+			if err := os.MkdirAll(filepath.Dir(logFile), 0755); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			file, err := os.OpenFile(logFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error opening log file: %v\n", err)
+				os.Exit(1)
+			}
+			defer file.Close()
+
+			logger := log.New(io.MultiWriter(os.Stdout, file), "", log.LstdFlags)
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			if err := appInstance.Watch(ctx, debounce, logger); err != nil {
+				logger.Printf("error: %v", err)
+				os.Exit(1)
+			}
+			logger.Printf("watcher stopped")
+		},
+	}
+
+	cmd.Flags().DurationVar(&debounce, "debounce", app.DefaultWatchDebounce, "Time to wait for writes to settle before syncing")
+	cmd.Flags().StringVar(&logFile, "log-file", "", "Log file (default: watch.log in the gart data directory)")
+	cmd.Flags().BoolVar(&installSystemd, "install-systemd", false, "Write a systemd user unit running 'gart watch' and exit")
+
+	return cmd
+}
+
+// installWatchUnit writes the systemd user unit running the watcher with the current flags
+func installWatchUnit(cmd *cobra.Command) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the gart executable: %w", err)
+	}
+
+	command := []string{executable, "watch"}
+	for _, name := range []string{"debounce", "log-file", "profile"} {
+		if flag := cmd.Flags().Lookup(name); flag != nil && flag.Changed {
+			command = append(command, "--"+name, flag.Value.String())
+		}
+	}
+
+	path, err := system.WriteSystemdUserUnit(watchUnitName, system.SystemdServiceUnit("Gart dotfiles watcher", command))
+	if err != nil {
+		return fmt.Errorf("failed to write systemd unit: %w", err)
+	}
+
+	fmt.Printf("Wrote %s\n", path)
+	fmt.Printf("Enable it with: systemctl --user daemon-reload && systemctl --user enable --now %s\n", watchUnitName)
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"github.com/bnema/gart/internal/security"
 	"github.com/bnema/gart/internal/system"
@@ -12,18 +13,157 @@ import (
 
 // Config represents the structure of the entire configuration file
 type Config struct {
-	Settings        SettingsConfig      `toml:"settings"`
-	Dotfiles        map[string]string   `toml:"dotfiles"`
-	DotfilesIgnores map[string][]string `toml:"dotfiles.ignores,omitempty"`
+	Settings          SettingsConfig      `toml:"settings"`
+	Dotfiles          map[string]string   `toml:"dotfiles"`
+	DotfilesIgnores   map[string][]string `toml:"dotfiles.ignores,omitempty"`
+	DotfilesModes     map[string]string   `toml:"dotfiles.modes,omitempty"`
+	DotfilesTemplates map[string]bool     `toml:"dotfiles.templates,omitempty"`
+	DotfilesEncrypt   map[string]bool     `toml:"dotfiles.encrypt,omitempty"`
+	Profiles          map[string]Profile  `toml:"profiles,omitempty"`
+
+	// basePaths keeps the [dotfiles] paths replaced by the path overrides of a profile
+	basePaths map[string]string
+}
+
+// Profile selects a subset of the dotfiles for some machines and optionally overrides their paths
+type Profile struct {
+	// Hosts are hostname glob patterns that select this profile automatically
+	Hosts []string `toml:"hosts,omitempty"`
+	// Dotfiles lists the active dotfiles, all dotfiles are active when empty
+	Dotfiles []string `toml:"dotfiles,omitempty"`
+	// Paths overrides the local path of some dotfiles
+	Paths map[string]string `toml:"paths,omitempty"`
 }
 
+// Management modes of a dotfile
+const (
+	// ModeCopy keeps a copy of the dotfile in the store (default)
+	ModeCopy = "copy"
+	// ModeSymlink moves the dotfile into the store and replaces it with a symlink
+	ModeSymlink = "symlink"
+)
+
 // SettingsConfig represents the general settings of the application
 type SettingsConfig struct {
 	StoragePath     string                   `toml:"storage_path"`
 	GitVersioning   bool                     `toml:"git_versioning"`
 	ReverseSyncMode bool                     `toml:"reverse_sync"`
+	Mode            string                   `toml:"mode,omitempty"`
+	TemplateData    map[string]string        `toml:"template_data,omitempty"`
 	Git             GitConfig                `toml:"git"`
 	Security        *security.SecurityConfig `toml:"security,omitempty"`
+	Backup          *BackupConfig            `toml:"backup,omitempty"`
+	Encryption      *EncryptionConfig        `toml:"encryption,omitempty"`
+	Hooks           *HooksConfig             `toml:"hooks,omitempty"`
+	// NonInteractive never reads stdin and answers prompts with PromptPolicy
+	NonInteractive bool `toml:"non_interactive,omitempty"`
+	// PromptPolicy is fail (default), skip or proceed
+	PromptPolicy string `toml:"prompt_policy,omitempty"`
+	// SyncJobs is the number of dotfiles synced concurrently, the number of CPUs when unset
+	SyncJobs int `toml:"sync_jobs,omitempty"`
+}
+
+// Hook events
+const (
+	HookPreAdd     = "pre_add"
+	HookPreSync    = "pre_sync"
+	HookPostSync   = "post_sync"
+	HookPostDeploy = "post_deploy"
+	HookPreCommit  = "pre_commit"
+)
+
+// Hook failure modes
+const (
+	// HookAbort stops the operation when the hook fails (default)
+	HookAbort = "abort"
+	// HookWarn only reports the failure and lets the operation continue
+	HookWarn = "warn"
+)
+
+// Hook is a shell command run at a point of the lifecycle of a dotfile
+type Hook struct {
+	// Run is the command line, executed with sh -c (cmd /C on Windows)
+	Run string `toml:"run"`
+	// OnFailure is abort (default) or warn
+	OnFailure string `toml:"on_failure,omitempty"`
+	// Timeout kills the command after a duration such as "10s", defaults to the hooks timeout
+	Timeout string `toml:"timeout,omitempty"`
+}
+
+// HookSet lists the hooks run for each event
+type HookSet struct {
+	PreAdd     []Hook `toml:"pre_add,omitempty"`
+	PreSync    []Hook `toml:"pre_sync,omitempty"`
+	PostSync   []Hook `toml:"post_sync,omitempty"`
+	PostDeploy []Hook `toml:"post_deploy,omitempty"`
+	PreCommit  []Hook `toml:"pre_commit,omitempty"`
+}
+
+// Event returns the hooks of an event
+func (h HookSet) Event(event string) []Hook {
+	switch event {
+	case HookPreAdd:
+		return h.PreAdd
+	case HookPreSync:
+		return h.PreSync
+	case HookPostSync:
+		return h.PostSync
+	case HookPostDeploy:
+		return h.PostDeploy
+	case HookPreCommit:
+		return h.PreCommit
+	}
+	return nil
+}
+
+// HooksConfig holds the global hooks, run for every dotfile, and the hooks of single dotfiles
+type HooksConfig struct {
+	// Timeout is the default timeout of the hooks, 30s when empty
+	Timeout string `toml:"timeout,omitempty"`
+	HookSet
+	Dotfiles map[string]HookSet `toml:"dotfiles,omitempty"`
+}
+
+// EncryptionConfig selects the age keys used for the dotfiles stored encrypted
+type EncryptionConfig struct {
+	// Identity is the age X25519 identity file, generated on first use
+	Identity string `toml:"identity,omitempty"`
+	// Recipients are additional age public keys able to decrypt the store, e.g. of other machines
+	Recipients []string `toml:"recipients,omitempty"`
+	// Passphrase derives the key from a passphrase instead, read from GART_PASSPHRASE or prompted
+	Passphrase bool `toml:"passphrase,omitempty"`
+}
+
+// DefaultIdentityPath returns the location of the age identity generated by gart
+func DefaultIdentityPath() string {
+	configDir, _, err := system.GetConfigPaths()
+	if err != nil {
+		return "age.key"
+	}
+	return filepath.Join(configDir, "age.key")
+}
+
+// BackupConfig controls the snapshots taken before gart overwrites local files
+type BackupConfig struct {
+	Enabled    bool   `toml:"enabled"`
+	Path       string `toml:"path"`
+	Keep       int    `toml:"keep"`
+	MaxAgeDays int    `toml:"max_age_days"`
+}
+
+// DefaultBackupConfig returns a backup configuration storing snapshots in the gart data dir
+func DefaultBackupConfig() *BackupConfig {
+	backupPath := ""
+	if gartDataDir, err := system.GetDataPaths(); err == nil {
+		backupPath = filepath.Join(gartDataDir, "backups")
+	}
+
+	return &BackupConfig{
+		Enabled:    true,
+		Path:       backupPath,
+		Keep:       20,
+		MaxAgeDays: 30,
+	}
 }
 
 // GitConfig represents the structure of the git configuration
@@ -31,6 +171,8 @@ type GitConfig struct {
 	Branch              string `toml:"branch"`
 	CommitMessageFormat string `toml:"commit_message_format"`
 	AutoPush            bool   `toml:"auto_push"`
+	AutoPull            bool   `toml:"auto_pull"`
+	PullStrategy        string `toml:"pull_strategy,omitempty"`
 }
 
 // LoadConfig loads the configuration from the file
@@ -56,17 +198,44 @@ func LoadConfig(configPath string) (*Config, error) {
 	if config.DotfilesIgnores == nil {
 		config.DotfilesIgnores = make(map[string][]string)
 	}
+	if config.DotfilesModes == nil {
+		config.DotfilesModes = make(map[string]string)
+	}
+	if config.DotfilesTemplates == nil {
+		config.DotfilesTemplates = make(map[string]bool)
+	}
+	if config.DotfilesEncrypt == nil {
+		config.DotfilesEncrypt = make(map[string]bool)
+	}
 
 	// Ensure Security config is initialized with defaults if not present
 	if config.Settings.Security == nil {
 		config.Settings.Security = security.DefaultSecurityConfig()
 	}
+	if err := config.Settings.Security.ValidateRules(); err != nil {
+		return nil, fmt.Errorf("invalid security rule: %w", err)
+	}
+
+	// Ensure Backup config is initialized with defaults if not present
+	if config.Settings.Backup == nil {
+		config.Settings.Backup = DefaultBackupConfig()
+	}
 
 	return &config, nil
 }
 
 // AddDotfileToConfig adds a new dotfile to the config file
 func AddDotfileToConfig(configPath string, name, path string, ignores []string) error {
+	return addDotfileToConfig(configPath, name, path, ignores, false)
+}
+
+// AddEncryptedDotfileToConfig adds a new dotfile marked for encrypted storage to the config
+// file, so it is never recorded as a plain dotfile
+func AddEncryptedDotfileToConfig(configPath string, name, path string, ignores []string) error {
+	return addDotfileToConfig(configPath, name, path, ignores, true)
+}
+
+func addDotfileToConfig(configPath string, name, path string, ignores []string, encrypt bool) error {
 	config, err := LoadConfig(configPath)
 	if err != nil {
 		if !os.IsNotExist(err) {
@@ -86,6 +255,206 @@ func AddDotfileToConfig(configPath string, name, path string, ignores []string)
 		}
 		config.DotfilesIgnores[name] = ignores
 	}
+	if encrypt {
+		if config.DotfilesEncrypt == nil {
+			config.DotfilesEncrypt = make(map[string]bool)
+		}
+		config.DotfilesEncrypt[name] = true
+	}
+
+	return SaveConfig(configPath, config)
+}
+
+// RemoveDotfileFromConfig removes a dotfile and all its settings from the config file. It is
+// also dropped from the dotfiles lists of the profiles, unless that would empty a list and
+// select every dotfile.
+func RemoveDotfileFromConfig(configPath string, name string) error {
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("error loading config file: %w", err)
+	}
+
+	delete(config.Dotfiles, name)
+	delete(config.DotfilesIgnores, name)
+	delete(config.DotfilesModes, name)
+	delete(config.DotfilesTemplates, name)
+	delete(config.DotfilesEncrypt, name)
+	for profileName, profile := range config.Profiles {
+		var dotfiles []string
+		for _, dotfile := range profile.Dotfiles {
+			if dotfile != name {
+				dotfiles = append(dotfiles, dotfile)
+			}
+		}
+		if len(dotfiles) > 0 {
+			profile.Dotfiles = dotfiles
+			config.Profiles[profileName] = profile
+		}
+	}
+
+	return SaveConfig(configPath, config)
+}
+
+// DotfileMode returns the management mode of a dotfile, falling back to the global mode
+func (c *Config) DotfileMode(name string) string {
+	if mode, ok := c.DotfilesModes[name]; ok && mode != "" {
+		return mode
+	}
+	if c.Settings.Mode != "" {
+		return c.Settings.Mode
+	}
+	return ModeCopy
+}
+
+// ProfileForHost returns the first profile, in alphabetical order, with a host pattern
+// matching hostname, or an empty string if none matches
+func (c *Config) ProfileForHost(hostname string) string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, pattern := range c.Profiles[name].Hosts {
+			if matched, _ := filepath.Match(pattern, hostname); matched {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// ApplyProfile restricts Dotfiles to the ones selected by the named profile
+// and applies its path overrides
+func (c *Config) ApplyProfile(name string) error {
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return fmt.Errorf("profile '%s' not found", name)
+	}
+
+	if len(profile.Dotfiles) > 0 {
+		selected := make(map[string]string, len(profile.Dotfiles))
+		for _, dotfile := range profile.Dotfiles {
+			if path, ok := c.Dotfiles[dotfile]; ok {
+				selected[dotfile] = path
+			}
+		}
+		c.Dotfiles = selected
+	}
+
+	for dotfile, path := range profile.Paths {
+		if base, ok := c.Dotfiles[dotfile]; ok {
+			if c.basePaths == nil {
+				c.basePaths = make(map[string]string)
+			}
+			if _, overridden := c.basePaths[dotfile]; !overridden {
+				c.basePaths[dotfile] = base
+			}
+			c.Dotfiles[dotfile] = path
+		}
+	}
+	return nil
+}
+
+// BasePath returns the path of a dotfile in the [dotfiles] section when the applied profile
+// overrides it. The store entry of a dotfile is named after that path on every host.
+func (c *Config) BasePath(name string) (string, bool) {
+	path, ok := c.basePaths[name]
+	return path, ok
+}
+
+// AddDotfileToProfile appends a dotfile to the dotfiles list of a profile in the config file.
+// Profiles without an explicit list already include every dotfile and are left untouched.
+func AddDotfileToProfile(configPath string, profileName, dotfileName string) error {
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("error loading config file: %w", err)
+	}
+
+	profile, ok := config.Profiles[profileName]
+	if !ok {
+		return fmt.Errorf("profile '%s' not found", profileName)
+	}
+	if len(profile.Dotfiles) == 0 {
+		return nil
+	}
+	for _, name := range profile.Dotfiles {
+		if name == dotfileName {
+			return nil
+		}
+	}
+
+	profile.Dotfiles = append(profile.Dotfiles, dotfileName)
+	config.Profiles[profileName] = profile
+	return SaveConfig(configPath, config)
+}
+
+// ValidateMode checks that the given management mode is supported
+func ValidateMode(mode string) error {
+	switch mode {
+	case ModeCopy, ModeSymlink:
+		return nil
+	default:
+		return fmt.Errorf("invalid mode '%s': must be one of: %s, %s", mode, ModeCopy, ModeSymlink)
+	}
+}
+
+// UpdateDotfileMode records the management mode of a dotfile in the config file
+func UpdateDotfileMode(configPath string, name, mode string) error {
+	if err := ValidateMode(mode); err != nil {
+		return err
+	}
+
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("error loading config file: %w", err)
+	}
+
+	if _, ok := config.Dotfiles[name]; !ok {
+		return fmt.Errorf("dotfile '%s' not found", name)
+	}
+
+	config.DotfilesModes[name] = mode
+	return SaveConfig(configPath, config)
+}
+
+// UpdateDotfileTemplate marks or unmarks a dotfile as a template in the config file
+func UpdateDotfileTemplate(configPath string, name string, enabled bool) error {
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("error loading config file: %w", err)
+	}
+
+	if _, ok := config.Dotfiles[name]; !ok {
+		return fmt.Errorf("dotfile '%s' not found", name)
+	}
+
+	if enabled {
+		config.DotfilesTemplates[name] = true
+	} else {
+		delete(config.DotfilesTemplates, name)
+	}
+
+	return SaveConfig(configPath, config)
+}
+
+// UpdateDotfileEncrypt marks or unmarks a dotfile for encrypted storage in the config file
+func UpdateDotfileEncrypt(configPath string, name string, enabled bool) error {
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("error loading config file: %w", err)
+	}
+
+	if _, ok := config.Dotfiles[name]; !ok {
+		return fmt.Errorf("dotfile '%s' not found", name)
+	}
+
+	if enabled {
+		config.DotfilesEncrypt[name] = true
+	} else {
+		delete(config.DotfilesEncrypt, name)
+	}
 
 	return SaveConfig(configPath, config)
 }
@@ -128,6 +497,7 @@ func CreateDefaultConfig() (*Config, error) {
 				AutoPush:            false,
 			},
 			Security: security.DefaultSecurityConfig(),
+			Backup:   DefaultBackupConfig(),
 		},
 		Dotfiles: make(map[string]string),
 	}
@@ -10,6 +10,13 @@ var (
 	ErrNoRemote    = errors.New("no remote origin configured")
 	ErrAuthFailed  = errors.New("authentication failed")
 	ErrNotRepository = errors.New("not a git repository")
+
+	ErrUncommittedChanges   = errors.New("the store has uncommitted changes")
+	ErrRemoteBranchNotFound = errors.New("remote branch not found")
+	ErrNonFastForward       = errors.New("local branch diverged from the remote, pull with the merge or rebase strategy")
+	ErrMergeConflict        = errors.New("files changed both locally and on the remote")
+	ErrRevisionNotFound     = errors.New("revision not found")
+	ErrPathNotFound         = errors.New("path not found in revision")
 )
 
 // GitError represents a git operation error with context
@@ -40,6 +47,11 @@ func IsAuthError(err error) bool {
 	return errors.Is(err, ErrAuthFailed)
 }
 
+// IsMergeConflictError checks if an error is due to files changed on both sides of a pull
+func IsMergeConflictError(err error) bool {
+	return errors.Is(err, ErrMergeConflict)
+}
+
 // IsNotRepositoryError checks if an error is due to not being in a git repository
 func IsNotRepositoryError(err error) bool {
 	return errors.Is(err, ErrNotRepository)
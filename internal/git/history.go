@@ -0,0 +1,428 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// CommitInfo describes a commit of the store
+type CommitInfo struct {
+	Hash    string
+	Author  string
+	Date    time.Time
+	Message string
+	// Branch is a branch containing the commit, the current branch when it does
+	Branch string
+}
+
+// ShortHash returns the abbreviated commit hash
+func (c CommitInfo) ShortHash() string {
+	if len(c.Hash) > 7 {
+		return c.Hash[:7]
+	}
+	return c.Hash
+}
+
+// Subject returns the first line of the commit message
+func (c CommitInfo) Subject() string {
+	subject, _, _ := strings.Cut(strings.TrimSpace(c.Message), "\n")
+	return subject
+}
+
+// FileChange is a file changed by a commit with its content before and after.
+// From is nil for a created file and To is nil for a deleted one.
+type FileChange struct {
+	Path string
+	From []byte
+	To   []byte
+}
+
+// logRepository returns the commits of every local and remote branch touching path,
+// newest first, annotated with the first branch containing them
+func logRepository(repo *git.Repository, path string, limit int) ([]CommitInfo, error) {
+	branches, err := branchTips(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[plumbing.Hash]bool)
+	var commits []CommitInfo
+	for _, branch := range branches {
+		iter, err := repo.Log(&git.LogOptions{
+			From:       branch.hash,
+			PathFilter: pathFilter(path),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read history of %s: %w", branch.name, err)
+		}
+
+		err = iter.ForEach(func(commit *object.Commit) error {
+			if seen[commit.Hash] {
+				return nil
+			}
+			seen[commit.Hash] = true
+			commits = append(commits, commitInfo(commit, branch.name))
+			return nil
+		})
+		iter.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read history of %s: %w", branch.name, err)
+		}
+	}
+
+	sort.SliceStable(commits, func(i, j int) bool {
+		return commits[i].Date.After(commits[j].Date)
+	})
+	if limit > 0 && len(commits) > limit {
+		commits = commits[:limit]
+	}
+	return commits, nil
+}
+
+// showRepository returns a commit and the files it changed under path
+func showRepository(repo *git.Repository, rev, path string) (*CommitInfo, []FileChange, error) {
+	commit, err := resolveCommit(repo, rev)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	branch, err := containingBranch(repo, commit)
+	if err != nil {
+		return nil, nil, err
+	}
+	info := commitInfo(commit, branch)
+
+	var parent *object.Commit
+	if commit.NumParents() > 0 {
+		if parent, err = commit.Parent(0); err != nil {
+			return nil, nil, fmt.Errorf("failed to read parent commit: %w", err)
+		}
+	}
+
+	files, err := diffCommits(parent, commit, path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &info, files, nil
+}
+
+// diffRepository returns the files under path that differ between two revisions
+func diffRepository(repo *git.Repository, fromRev, toRev, path string) ([]FileChange, error) {
+	from, err := resolveCommit(repo, fromRev)
+	if err != nil {
+		return nil, err
+	}
+	to, err := resolveCommit(repo, toRev)
+	if err != nil {
+		return nil, err
+	}
+	return diffCommits(from, to, path)
+}
+
+// diffCommits returns the files under path that differ between two commits, sorted by path.
+// A nil from commit is the empty tree.
+func diffCommits(from, to *object.Commit, path string) ([]FileChange, error) {
+	toTree, err := to.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tree: %w", err)
+	}
+	var fromTree *object.Tree
+	if from != nil {
+		if fromTree, err = from.Tree(); err != nil {
+			return nil, fmt.Errorf("failed to read tree: %w", err)
+		}
+	}
+
+	changes, err := object.DiffTree(fromTree, toTree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff commits: %w", err)
+	}
+
+	filter := pathFilter(path)
+	var files []FileChange
+	for _, change := range changes {
+		name := change.To.Name
+		if name == "" {
+			name = change.From.Name
+		}
+		if filter != nil && !filter(name) {
+			continue
+		}
+
+		fromFile, toFile, err := change.Files()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		file := FileChange{Path: name}
+		if file.From, err = fileContent(fromFile); err != nil {
+			return nil, err
+		}
+		if file.To, err = fileContent(toFile); err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+// revisionAt returns the hash of the newest commit of the current branch made at or before at
+func revisionAt(repo *git.Repository, at time.Time) (string, error) {
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("%w: the store has no commits", ErrRevisionNotFound)
+	}
+
+	iter, err := repo.Log(&git.LogOptions{From: head.Hash()})
+	if err != nil {
+		return "", fmt.Errorf("failed to read history: %w", err)
+	}
+	defer iter.Close()
+
+	var found *object.Commit
+	err = iter.ForEach(func(commit *object.Commit) error {
+		when := commit.Author.When
+		if !when.After(at) && (found == nil || when.After(found.Author.When)) {
+			found = commit
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to read history: %w", err)
+	}
+	if found == nil {
+		return "", fmt.Errorf("%w: no commit before %s", ErrRevisionNotFound, at.Format(time.RFC3339))
+	}
+	return found.Hash.String(), nil
+}
+
+// restoreRepository checks out the files under path as they were at a revision and
+// stages them. Files under path added since the revision are removed.
+func restoreRepository(repo *git.Repository, rev, path string) (*CommitInfo, error) {
+	filter := pathFilter(path)
+	if filter == nil {
+		return nil, fmt.Errorf("a path is required to restore")
+	}
+
+	commit, err := resolveCommit(repo, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := treeFiles(commit, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("%w: %s at %s", ErrPathNotFound, path, rev)
+	}
+
+	if head, err := repo.Head(); err == nil {
+		headCommit, err := repo.CommitObject(head.Hash())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read HEAD commit: %w", err)
+		}
+		current, err := treeFiles(headCommit, filter)
+		if err != nil {
+			return nil, err
+		}
+		for name := range current {
+			paths[name] = true
+		}
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worktree: %w", err)
+	}
+
+	names := make([]string, 0, len(paths))
+	for name := range paths {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := checkoutFile(worktree, commit, name); err != nil {
+			return nil, err
+		}
+	}
+
+	branch, err := containingBranch(repo, commit)
+	if err != nil {
+		return nil, err
+	}
+	info := commitInfo(commit, branch)
+	return &info, nil
+}
+
+// treeFiles returns the files of a commit matched by filter
+func treeFiles(commit *object.Commit, filter func(string) bool) (map[string]bool, error) {
+	files, err := commit.Files()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tree: %w", err)
+	}
+
+	paths := make(map[string]bool)
+	err = files.ForEach(func(file *object.File) error {
+		if filter(file.Name) {
+			paths[file.Name] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tree: %w", err)
+	}
+	return paths, nil
+}
+
+// resolveCommit resolves a revision such as a hash, a short hash, a branch or HEAD~2
+func resolveCommit(repo *git.Repository, rev string) (*object.Commit, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrRevisionNotFound, rev)
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit %s: %w", rev, err)
+	}
+	return commit, nil
+}
+
+// resolveBranch returns the full reference name of a branch, preferring a remote branch
+// pushed by another machine over a local branch of the same name
+func resolveBranch(repo *git.Repository, name string) (string, error) {
+	refs, err := repo.References()
+	if err != nil {
+		return "", fmt.Errorf("failed to list branches: %w", err)
+	}
+
+	var remote, local string
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		refName := ref.Name()
+		switch {
+		case refName.IsRemote() && remote == "" && strings.HasSuffix(refName.Short(), "/"+name):
+			remote = refName.String()
+		case refName.IsBranch() && refName.Short() == name:
+			local = refName.String()
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list branches: %w", err)
+	}
+
+	if remote != "" {
+		return remote, nil
+	}
+	if local != "" {
+		return local, nil
+	}
+	return "", fmt.Errorf("%w: branch %s", ErrRevisionNotFound, name)
+}
+
+type branchTip struct {
+	name string
+	hash plumbing.Hash
+}
+
+// branchTips returns the current branch followed by the other local and remote branches
+func branchTips(repo *git.Repository) ([]branchTip, error) {
+	current := ""
+	var tips []branchTip
+	if head, err := repo.Head(); err == nil {
+		current = head.Name().String()
+		tips = append(tips, branchTip{name: head.Name().Short(), hash: head.Hash()})
+	} else if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return nil, fmt.Errorf("failed to read HEAD: %w", err)
+	}
+
+	refs, err := repo.References()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+	var others []branchTip
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() != plumbing.HashReference || ref.Name().String() == current {
+			return nil
+		}
+		if ref.Name().IsBranch() || ref.Name().IsRemote() {
+			others = append(others, branchTip{name: ref.Name().Short(), hash: ref.Hash()})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %w", err)
+	}
+
+	sort.Slice(others, func(i, j int) bool { return others[i].name < others[j].name })
+	return append(tips, others...), nil
+}
+
+// containingBranch returns the first branch of branchTips containing commit, empty when
+// no branch does
+func containingBranch(repo *git.Repository, commit *object.Commit) (string, error) {
+	branches, err := branchTips(repo)
+	if err != nil {
+		return "", err
+	}
+
+	for _, branch := range branches {
+		if branch.hash == commit.Hash {
+			return branch.name, nil
+		}
+		tip, err := repo.CommitObject(branch.hash)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", branch.name, err)
+		}
+		contained, err := commit.IsAncestor(tip)
+		if err != nil {
+			return "", fmt.Errorf("failed to read history of %s: %w", branch.name, err)
+		}
+		if contained {
+			return branch.name, nil
+		}
+	}
+	return "", nil
+}
+
+// pathFilter matches path itself and everything below it, nil matches everything
+func pathFilter(path string) func(string) bool {
+	path = strings.Trim(path, "/")
+	if path == "" || path == "." {
+		return nil
+	}
+	return func(name string) bool {
+		return name == path || strings.HasPrefix(name, path+"/")
+	}
+}
+
+func commitInfo(commit *object.Commit, branch string) CommitInfo {
+	return CommitInfo{
+		Hash:    commit.Hash.String(),
+		Author:  commit.Author.Name,
+		Date:    commit.Author.When,
+		Message: commit.Message,
+		Branch:  branch,
+	}
+}
+
+func fileContent(file *object.File) ([]byte, error) {
+	if file == nil {
+		return nil, nil
+	}
+	reader, err := file.Reader()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file.Name, err)
+	}
+	defer func() { _ = reader.Close() }()
+	return io.ReadAll(reader)
+}
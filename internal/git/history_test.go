@@ -0,0 +1,183 @@
+package git
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newHistoryRepository returns an in-memory repository with three commits
+func newHistoryRepository(t *testing.T) *MemoryRepository {
+	t.Helper()
+	repo := NewMemoryRepository("/test").(*MemoryRepository)
+	require.NoError(t, repo.Init("laptop"))
+
+	steps := []struct{ file, content, message string }{
+		{"nvim/init.lua", "-- v1\n", "Add nvim"},
+		{"bashrc", "export A=1\n", "Add bashrc"},
+		{"nvim/init.lua", "-- v2\n", "Update nvim"},
+	}
+	for _, step := range steps {
+		require.NoError(t, repo.CreateFile(step.file, step.content))
+		require.NoError(t, repo.Add("."))
+		require.NoError(t, repo.Commit(step.message))
+	}
+	return repo
+}
+
+func TestMemoryRepository_Log(t *testing.T) {
+	repo := newHistoryRepository(t)
+
+	commits, err := repo.Log("nvim", 0)
+	require.NoError(t, err)
+	require.Len(t, commits, 2)
+	assert.Equal(t, "Update nvim", commits[0].Subject())
+	assert.Equal(t, "Add nvim", commits[1].Subject())
+	assert.Equal(t, "laptop", commits[0].Branch)
+	assert.Len(t, commits[0].ShortHash(), 7)
+
+	all, err := repo.Log("", 0)
+	require.NoError(t, err)
+	assert.Len(t, all, 3)
+
+	limited, err := repo.Log("", 1)
+	require.NoError(t, err)
+	assert.Len(t, limited, 1)
+
+	// A path prefix does not match a sibling with the same prefix
+	none, err := repo.Log("nv", 0)
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
+
+func TestMemoryRepository_Show(t *testing.T) {
+	repo := newHistoryRepository(t)
+
+	info, files, err := repo.Show("HEAD", "nvim")
+	require.NoError(t, err)
+	assert.Equal(t, "Update nvim", info.Subject())
+	require.Len(t, files, 1)
+	assert.Equal(t, "nvim/init.lua", files[0].Path)
+	assert.Equal(t, "-- v1\n", string(files[0].From))
+	assert.Equal(t, "-- v2\n", string(files[0].To))
+
+	// The root commit creates its files
+	_, files, err = repo.Show("HEAD~2", "")
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Nil(t, files[0].From)
+
+	// Changes outside the path are filtered out
+	_, files, err = repo.Show("HEAD~1", "nvim")
+	require.NoError(t, err)
+	assert.Empty(t, files)
+
+	_, _, err = repo.Show("does-not-exist", "")
+	assert.ErrorIs(t, err, ErrRevisionNotFound)
+}
+
+func TestMemoryRepository_Show_ShortHash(t *testing.T) {
+	repo := newHistoryRepository(t)
+
+	commits, err := repo.Log("bashrc", 0)
+	require.NoError(t, err)
+	require.Len(t, commits, 1)
+
+	info, _, err := repo.Show(commits[0].ShortHash(), "")
+	require.NoError(t, err)
+	assert.Equal(t, commits[0].Hash, info.Hash)
+}
+
+func TestMemoryRepository_RevisionAt(t *testing.T) {
+	repo := newHistoryRepository(t)
+
+	commits, err := repo.Log("", 1)
+	require.NoError(t, err)
+
+	hash, err := repo.RevisionAt(time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, commits[0].Hash, hash)
+
+	_, err = repo.RevisionAt(time.Now().Add(-24 * time.Hour))
+	assert.ErrorIs(t, err, ErrRevisionNotFound)
+}
+
+func TestMemoryRepository_Restore(t *testing.T) {
+	repo := newHistoryRepository(t)
+	require.NoError(t, repo.CreateFile("nvim/plugins.lua", "-- plugins\n"))
+	require.NoError(t, repo.Add("."))
+	require.NoError(t, repo.Commit("Add nvim plugins"))
+
+	info, err := repo.Restore("HEAD~3", "nvim")
+	require.NoError(t, err)
+	assert.Equal(t, "Add nvim", info.Subject())
+
+	file, err := repo.fs.Open("nvim/init.lua")
+	require.NoError(t, err)
+	content, err := io.ReadAll(file)
+	_ = file.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "-- v1\n", string(content))
+
+	// Files added since the revision are removed, other dotfiles are untouched
+	_, err = repo.fs.Stat("nvim/plugins.lua")
+	assert.Error(t, err)
+	_, err = repo.fs.Stat("bashrc")
+	assert.NoError(t, err)
+
+	status, err := repo.Status()
+	require.NoError(t, err)
+	assert.Len(t, status, 2)
+
+	_, err = repo.Restore("HEAD", "missing")
+	assert.ErrorIs(t, err, ErrPathNotFound)
+}
+
+func TestRepository_DiffHostBranch(t *testing.T) {
+	remote := t.TempDir()
+	_, err := git.PlainInit(remote, true)
+	require.NoError(t, err)
+
+	laptop, laptopDir := newMachine(t, remote)
+	commitFile(t, laptop, laptopDir, "nvim/init.lua", "-- laptop\n")
+	require.NoError(t, laptop.Push())
+
+	desktopDir := t.TempDir()
+	desktop, err := NewRepository(desktopDir)
+	require.NoError(t, err)
+	require.NoError(t, desktop.Init("desktop"))
+	require.NoError(t, desktop.SetRemote("origin", remote))
+	commitFile(t, desktop, desktopDir, "nvim/init.lua", "-- desktop\n")
+	commitFile(t, desktop, desktopDir, "bashrc", "export A=1\n")
+	require.NoError(t, desktop.Fetch())
+
+	ref, err := desktop.ResolveBranch("main")
+	require.NoError(t, err)
+	assert.Equal(t, "refs/remotes/origin/main", ref)
+
+	files, err := desktop.Diff("HEAD", ref, "nvim")
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "-- desktop\n", string(files[0].From))
+	assert.Equal(t, "-- laptop\n", string(files[0].To))
+
+	// The local branch is found when there is no remote branch of that name
+	ref, err = desktop.ResolveBranch("desktop")
+	require.NoError(t, err)
+	assert.Equal(t, "refs/heads/desktop", ref)
+
+	_, err = desktop.ResolveBranch("server")
+	assert.ErrorIs(t, err, ErrRevisionNotFound)
+
+	// Commits are shown with a branch containing them, not the current branch
+	info, _, err := desktop.Show("HEAD~1", "")
+	require.NoError(t, err)
+	assert.Equal(t, "desktop", info.Branch)
+	info, _, err = desktop.Show("refs/remotes/origin/main", "")
+	require.NoError(t, err)
+	assert.Equal(t, "origin/main", info.Branch)
+}
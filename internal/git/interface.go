@@ -1,6 +1,9 @@
 package git
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // GitRepository defines the interface for git operations
 // This interface allows for easy testing by providing mockable methods
@@ -20,9 +23,46 @@ type GitRepository interface {
 	// PushContext pushes commits to the remote repository with context for cancellation
 	PushContext(ctx context.Context) error
 
+	// Fetch downloads objects and refs from the remote repository
+	Fetch() error
+
+	// FetchContext downloads objects and refs from the remote repository with context for cancellation
+	FetchContext(ctx context.Context) error
+
+	// Pull fetches the remote branch and integrates it into the current branch
+	Pull(opts PullOptions) error
+
+	// PullContext fetches and integrates the remote branch with context for cancellation
+	PullContext(ctx context.Context, opts PullOptions) error
+
+	// Log returns the commits of all branches touching path, or every commit when path is
+	// empty, newest first. A positive limit caps the number of commits returned.
+	Log(path string, limit int) ([]CommitInfo, error)
+
+	// Show returns the commit a revision resolves to and the files it changed under path
+	Show(rev, path string) (*CommitInfo, []FileChange, error)
+
+	// Diff returns the files under path that differ between two revisions
+	Diff(fromRev, toRev, path string) ([]FileChange, error)
+
+	// ResolveBranch returns the reference of a branch, preferring the remote branch
+	// pushed by another machine over a local branch of the same name
+	ResolveBranch(name string) (string, error)
+
+	// RevisionAt returns the hash of the newest commit of the current branch made at or before at
+	RevisionAt(at time.Time) (string, error)
+
+	// Restore checks out the files under path as they were at a revision and stages them,
+	// removing the files added since. It returns the restored commit.
+	Restore(rev, path string) (*CommitInfo, error)
+
 	// Status returns a list of changed files
 	Status() ([]string, error)
 
+	// BranchStatus compares the current branch with its branch on the remote as of the
+	// last fetch, it returns an ErrNoRemote error when no remote is configured
+	BranchStatus() (*BranchStatus, error)
+
 	// HasRemote checks if a remote named "origin" is configured
 	HasRemote() (bool, error)
 
@@ -162,6 +162,131 @@ func (r *MemoryRepository) PushContext(ctx context.Context) error {
 	return nil
 }
 
+// Fetch downloads objects and refs from the remote repository
+func (r *MemoryRepository) Fetch() error {
+	return r.FetchContext(context.Background())
+}
+
+// FetchContext downloads objects and refs from the "origin" remote with context
+func (r *MemoryRepository) FetchContext(ctx context.Context) error {
+	if err := r.requireOrigin("fetch"); err != nil {
+		return err
+	}
+	return fetchRemote(ctx, r.repo, "origin", nil)
+}
+
+// Pull fetches the remote branch and integrates it into the current branch
+func (r *MemoryRepository) Pull(opts PullOptions) error {
+	return r.PullContext(context.Background(), opts)
+}
+
+// PullContext fetches and integrates the branch of the "origin" remote with context
+func (r *MemoryRepository) PullContext(ctx context.Context, opts PullOptions) error {
+	if err := r.requireOrigin("pull"); err != nil {
+		return err
+	}
+	return pullRepository(ctx, r.repo, r.workingDir, "origin", nil, opts, object.Signature{
+		Name:  "Gart Test",
+		Email: "test@localhost",
+	})
+}
+
+// requireOrigin checks that the repository is initialized and has an "origin" remote
+func (r *MemoryRepository) requireOrigin(op string) error {
+	if r.repo == nil {
+		return &GitError{
+			Op:   op,
+			Path: r.workingDir,
+			Err:  ErrNotRepository,
+		}
+	}
+
+	hasRemote, err := r.HasRemote()
+	if err != nil {
+		return fmt.Errorf("failed to check remote: %w", err)
+	}
+	if !hasRemote {
+		return &GitError{
+			Op:   op,
+			Path: r.workingDir,
+			Err:  ErrNoRemote,
+		}
+	}
+	return nil
+}
+
+// Log returns the commits of all branches touching path, newest first
+func (r *MemoryRepository) Log(path string, limit int) ([]CommitInfo, error) {
+	if r.repo == nil {
+		return nil, &GitError{
+			Op:   "log",
+			Path: r.workingDir,
+			Err:  ErrNotRepository,
+		}
+	}
+	return logRepository(r.repo, path, limit)
+}
+
+// Show returns the commit a revision resolves to and the files it changed under path
+func (r *MemoryRepository) Show(rev, path string) (*CommitInfo, []FileChange, error) {
+	if r.repo == nil {
+		return nil, nil, &GitError{
+			Op:   "show",
+			Path: r.workingDir,
+			Err:  ErrNotRepository,
+		}
+	}
+	return showRepository(r.repo, rev, path)
+}
+
+// Diff returns the files under path that differ between two revisions
+func (r *MemoryRepository) Diff(fromRev, toRev, path string) ([]FileChange, error) {
+	if r.repo == nil {
+		return nil, &GitError{
+			Op:   "diff",
+			Path: r.workingDir,
+			Err:  ErrNotRepository,
+		}
+	}
+	return diffRepository(r.repo, fromRev, toRev, path)
+}
+
+// ResolveBranch returns the reference of a branch, preferring a remote branch
+func (r *MemoryRepository) ResolveBranch(name string) (string, error) {
+	if r.repo == nil {
+		return "", &GitError{
+			Op:   "rev-parse",
+			Path: r.workingDir,
+			Err:  ErrNotRepository,
+		}
+	}
+	return resolveBranch(r.repo, name)
+}
+
+// RevisionAt returns the hash of the newest commit of the current branch made at or before at
+func (r *MemoryRepository) RevisionAt(at time.Time) (string, error) {
+	if r.repo == nil {
+		return "", &GitError{
+			Op:   "rev-parse",
+			Path: r.workingDir,
+			Err:  ErrNotRepository,
+		}
+	}
+	return revisionAt(r.repo, at)
+}
+
+// Restore checks out the files under path as they were at a revision and stages them
+func (r *MemoryRepository) Restore(rev, path string) (*CommitInfo, error) {
+	if r.repo == nil {
+		return nil, &GitError{
+			Op:   "restore",
+			Path: r.workingDir,
+			Err:  ErrNotRepository,
+		}
+	}
+	return restoreRepository(r.repo, rev, path)
+}
+
 // Status returns a list of changed files
 func (r *MemoryRepository) Status() ([]string, error) {
 	if r.repo == nil {
@@ -190,6 +315,14 @@ func (r *MemoryRepository) Status() ([]string, error) {
 	return files, nil
 }
 
+// BranchStatus compares the current branch with its branch on the "origin" remote
+func (r *MemoryRepository) BranchStatus() (*BranchStatus, error) {
+	if err := r.requireOrigin("status"); err != nil {
+		return nil, err
+	}
+	return branchStatus(r.repo, "origin")
+}
+
 // HasRemote checks if a remote named "origin" is configured
 func (r *MemoryRepository) HasRemote() (bool, error) {
 	if r.repo == nil {
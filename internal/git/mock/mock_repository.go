@@ -12,7 +12,9 @@ package mock
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
+	git "github.com/bnema/gart/internal/git"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -58,6 +60,21 @@ func (mr *MockGitRepositoryMockRecorder) Add(patterns ...any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockGitRepository)(nil).Add), patterns...)
 }
 
+// BranchStatus mocks base method.
+func (m *MockGitRepository) BranchStatus() (*git.BranchStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BranchStatus")
+	ret0, _ := ret[0].(*git.BranchStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BranchStatus indicates an expected call of BranchStatus.
+func (mr *MockGitRepositoryMockRecorder) BranchStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BranchStatus", reflect.TypeOf((*MockGitRepository)(nil).BranchStatus))
+}
+
 // Commit mocks base method.
 func (m *MockGitRepository) Commit(message string) error {
 	m.ctrl.T.Helper()
@@ -72,6 +89,21 @@ func (mr *MockGitRepositoryMockRecorder) Commit(message any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Commit", reflect.TypeOf((*MockGitRepository)(nil).Commit), message)
 }
 
+// Diff mocks base method.
+func (m *MockGitRepository) Diff(fromRev, toRev, path string) ([]git.FileChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Diff", fromRev, toRev, path)
+	ret0, _ := ret[0].([]git.FileChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Diff indicates an expected call of Diff.
+func (mr *MockGitRepositoryMockRecorder) Diff(fromRev, toRev, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Diff", reflect.TypeOf((*MockGitRepository)(nil).Diff), fromRev, toRev, path)
+}
+
 // Exists mocks base method.
 func (m *MockGitRepository) Exists() (bool, error) {
 	m.ctrl.T.Helper()
@@ -87,6 +119,34 @@ func (mr *MockGitRepositoryMockRecorder) Exists() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockGitRepository)(nil).Exists))
 }
 
+// Fetch mocks base method.
+func (m *MockGitRepository) Fetch() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fetch")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Fetch indicates an expected call of Fetch.
+func (mr *MockGitRepositoryMockRecorder) Fetch() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fetch", reflect.TypeOf((*MockGitRepository)(nil).Fetch))
+}
+
+// FetchContext mocks base method.
+func (m *MockGitRepository) FetchContext(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchContext", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FetchContext indicates an expected call of FetchContext.
+func (mr *MockGitRepositoryMockRecorder) FetchContext(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchContext", reflect.TypeOf((*MockGitRepository)(nil).FetchContext), ctx)
+}
+
 // GetWorkingDirectory mocks base method.
 func (m *MockGitRepository) GetWorkingDirectory() string {
 	m.ctrl.T.Helper()
@@ -130,6 +190,49 @@ func (mr *MockGitRepositoryMockRecorder) Init(branch any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockGitRepository)(nil).Init), branch)
 }
 
+// Log mocks base method.
+func (m *MockGitRepository) Log(path string, limit int) ([]git.CommitInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Log", path, limit)
+	ret0, _ := ret[0].([]git.CommitInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Log indicates an expected call of Log.
+func (mr *MockGitRepositoryMockRecorder) Log(path, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Log", reflect.TypeOf((*MockGitRepository)(nil).Log), path, limit)
+}
+
+// Pull mocks base method.
+func (m *MockGitRepository) Pull(opts git.PullOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pull", opts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Pull indicates an expected call of Pull.
+func (mr *MockGitRepositoryMockRecorder) Pull(opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pull", reflect.TypeOf((*MockGitRepository)(nil).Pull), opts)
+}
+
+// PullContext mocks base method.
+func (m *MockGitRepository) PullContext(ctx context.Context, opts git.PullOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PullContext", ctx, opts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PullContext indicates an expected call of PullContext.
+func (mr *MockGitRepositoryMockRecorder) PullContext(ctx, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PullContext", reflect.TypeOf((*MockGitRepository)(nil).PullContext), ctx, opts)
+}
+
 // Push mocks base method.
 func (m *MockGitRepository) Push() error {
 	m.ctrl.T.Helper()
@@ -158,6 +261,51 @@ func (mr *MockGitRepositoryMockRecorder) PushContext(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PushContext", reflect.TypeOf((*MockGitRepository)(nil).PushContext), ctx)
 }
 
+// ResolveBranch mocks base method.
+func (m *MockGitRepository) ResolveBranch(name string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveBranch", name)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveBranch indicates an expected call of ResolveBranch.
+func (mr *MockGitRepositoryMockRecorder) ResolveBranch(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveBranch", reflect.TypeOf((*MockGitRepository)(nil).ResolveBranch), name)
+}
+
+// Restore mocks base method.
+func (m *MockGitRepository) Restore(rev, path string) (*git.CommitInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", rev, path)
+	ret0, _ := ret[0].(*git.CommitInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockGitRepositoryMockRecorder) Restore(rev, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockGitRepository)(nil).Restore), rev, path)
+}
+
+// RevisionAt mocks base method.
+func (m *MockGitRepository) RevisionAt(at time.Time) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevisionAt", at)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevisionAt indicates an expected call of RevisionAt.
+func (mr *MockGitRepositoryMockRecorder) RevisionAt(at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevisionAt", reflect.TypeOf((*MockGitRepository)(nil).RevisionAt), at)
+}
+
 // SetRemote mocks base method.
 func (m *MockGitRepository) SetRemote(name, url string) error {
 	m.ctrl.T.Helper()
@@ -172,6 +320,22 @@ func (mr *MockGitRepositoryMockRecorder) SetRemote(name, url any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRemote", reflect.TypeOf((*MockGitRepository)(nil).SetRemote), name, url)
 }
 
+// Show mocks base method.
+func (m *MockGitRepository) Show(rev, path string) (*git.CommitInfo, []git.FileChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Show", rev, path)
+	ret0, _ := ret[0].(*git.CommitInfo)
+	ret1, _ := ret[1].([]git.FileChange)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Show indicates an expected call of Show.
+func (mr *MockGitRepositoryMockRecorder) Show(rev, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Show", reflect.TypeOf((*MockGitRepository)(nil).Show), rev, path)
+}
+
 // Status mocks base method.
 func (m *MockGitRepository) Status() ([]string, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,425 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+// PullStrategy selects how remote commits are integrated into a diverged local branch
+type PullStrategy string
+
+const (
+	// PullFastForward only moves the local branch forward and fails if it diverged
+	PullFastForward PullStrategy = "ff-only"
+	// PullMerge creates a merge commit joining the local and remote branches
+	PullMerge PullStrategy = "merge"
+	// PullRebase replays the local commits on top of the remote branch
+	PullRebase PullStrategy = "rebase"
+)
+
+// ParsePullStrategy validates a pull strategy name, an empty name means fast-forward only
+func ParsePullStrategy(name string) (PullStrategy, error) {
+	switch PullStrategy(name) {
+	case "":
+		return PullFastForward, nil
+	case PullFastForward, PullMerge, PullRebase:
+		return PullStrategy(name), nil
+	default:
+		return "", fmt.Errorf("invalid pull strategy '%s': must be one of: %s, %s, %s",
+			name, PullFastForward, PullMerge, PullRebase)
+	}
+}
+
+// PullOptions configures a pull
+type PullOptions struct {
+	// Branch is the remote branch to integrate, defaults to the current branch
+	Branch string
+	// Strategy is used when the local branch diverged, defaults to PullFastForward
+	Strategy PullStrategy
+}
+
+// BranchStatus tells how far the current branch is from its branch on the remote
+type BranchStatus struct {
+	Branch string
+	// Upstream is the remote branch compared with, empty when it does not exist yet
+	Upstream string
+	// Ahead counts the local commits missing from Upstream, every commit without one
+	Ahead int
+	// Behind counts the commits of Upstream missing locally
+	Behind int
+}
+
+// fetchRemote downloads the refs and objects of a remote, being up to date is not an error
+func fetchRemote(ctx context.Context, repo *git.Repository, remoteName string, auth transport.AuthMethod) error {
+	err := repo.FetchContext(ctx, &git.FetchOptions{
+		RemoteName: remoteName,
+		Auth:       auth,
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("failed to fetch: %w", err)
+	}
+	return nil
+}
+
+// pullRepository fetches a remote and integrates its branch into the current branch.
+// Merges and rebases are resolved per file: a file changed on both sides is a conflict.
+func pullRepository(ctx context.Context, repo *git.Repository, workingDir, remoteName string, auth transport.AuthMethod, opts PullOptions, author object.Signature) error {
+	strategy, err := ParsePullStrategy(string(opts.Strategy))
+	if err != nil {
+		return err
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to get worktree: %w", err)
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return fmt.Errorf("failed to get status: %w", err)
+	}
+	if !status.IsClean() {
+		return &GitError{Op: "pull", Path: workingDir, Err: ErrUncommittedChanges}
+	}
+
+	if err := fetchRemote(ctx, repo, remoteName, auth); err != nil {
+		return err
+	}
+
+	// HEAD points to the local branch, which has no commit yet on a fresh store
+	headRef, err := repo.Storer.Reference(plumbing.HEAD)
+	if err != nil {
+		return fmt.Errorf("failed to read HEAD: %w", err)
+	}
+	branchRef := headRef.Target()
+	if headRef.Type() != plumbing.SymbolicReference {
+		return fmt.Errorf("cannot pull on a detached HEAD")
+	}
+
+	branch := opts.Branch
+	if branch == "" {
+		branch = branchRef.Short()
+	}
+	remoteRef, err := repo.Reference(plumbing.NewRemoteReferenceName(remoteName, branch), true)
+	if err != nil {
+		return &GitError{Op: "pull", Path: workingDir, Err: fmt.Errorf("%w: %s/%s", ErrRemoteBranchNotFound, remoteName, branch)}
+	}
+	remoteCommit, err := repo.CommitObject(remoteRef.Hash())
+	if err != nil {
+		return fmt.Errorf("failed to read remote commit: %w", err)
+	}
+
+	localRef, err := repo.Reference(branchRef, true)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return fastForward(repo, worktree, branchRef, remoteCommit.Hash)
+	} else if err != nil {
+		return fmt.Errorf("failed to read local branch: %w", err)
+	}
+	localCommit, err := repo.CommitObject(localRef.Hash())
+	if err != nil {
+		return fmt.Errorf("failed to read local commit: %w", err)
+	}
+
+	if localCommit.Hash == remoteCommit.Hash {
+		return nil
+	}
+	if contained, err := remoteCommit.IsAncestor(localCommit); err != nil {
+		return fmt.Errorf("failed to compare branches: %w", err)
+	} else if contained {
+		return nil
+	}
+	if behind, err := localCommit.IsAncestor(remoteCommit); err != nil {
+		return fmt.Errorf("failed to compare branches: %w", err)
+	} else if behind {
+		return fastForward(repo, worktree, branchRef, remoteCommit.Hash)
+	}
+
+	if strategy == PullFastForward {
+		return &GitError{Op: "pull", Path: workingDir, Err: ErrNonFastForward}
+	}
+
+	bases, err := localCommit.MergeBase(remoteCommit)
+	if err != nil || len(bases) == 0 {
+		return fmt.Errorf("failed to find a common ancestor with %s/%s: %v", remoteName, branch, err)
+	}
+	base := bases[0]
+
+	localChanges, err := changedFiles(base, localCommit)
+	if err != nil {
+		return err
+	}
+	remoteChanges, err := changedFiles(base, remoteCommit)
+	if err != nil {
+		return err
+	}
+	if conflicts := conflictingFiles(localChanges, remoteChanges); len(conflicts) > 0 {
+		return &GitError{Op: "pull", Path: workingDir, Err: fmt.Errorf("%w: %s", ErrMergeConflict, strings.Join(conflicts, ", "))}
+	}
+
+	if strategy == PullRebase {
+		return rebaseOnto(repo, worktree, branchRef, base, localCommit, remoteCommit)
+	}
+	return mergeInto(worktree, localCommit, remoteCommit, remoteChanges, localChanges,
+		fmt.Sprintf("Merge %s/%s", remoteName, branch), author)
+}
+
+// branchStatus compares the current branch with the branch of the same name on a remote,
+// using the remote refs of the last fetch
+func branchStatus(repo *git.Repository, remoteName string) (*BranchStatus, error) {
+	headRef, err := repo.Storer.Reference(plumbing.HEAD)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read HEAD: %w", err)
+	}
+	if headRef.Type() != plumbing.SymbolicReference {
+		return nil, fmt.Errorf("cannot compare a detached HEAD with the remote")
+	}
+	status := &BranchStatus{Branch: headRef.Target().Short()}
+
+	local, err := reachableCommits(repo, headRef.Target())
+	if err != nil {
+		return nil, err
+	}
+	upstreamRef := plumbing.NewRemoteReferenceName(remoteName, status.Branch)
+	upstream, err := reachableCommits(repo, upstreamRef)
+	if err != nil {
+		return nil, err
+	}
+	if upstream != nil {
+		status.Upstream = upstreamRef.Short()
+	}
+
+	for hash := range local {
+		if !upstream[hash] {
+			status.Ahead++
+		}
+	}
+	for hash := range upstream {
+		if !local[hash] {
+			status.Behind++
+		}
+	}
+	return status, nil
+}
+
+// reachableCommits returns the commits reachable from a reference, nil when it does not exist
+func reachableCommits(repo *git.Repository, name plumbing.ReferenceName) (map[plumbing.Hash]bool, error) {
+	ref, err := repo.Reference(name, true)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name.Short(), err)
+	}
+
+	iter, err := repo.Log(&git.LogOptions{From: ref.Hash()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history of %s: %w", name.Short(), err)
+	}
+	defer iter.Close()
+
+	commits := make(map[plumbing.Hash]bool)
+	err = iter.ForEach(func(commit *object.Commit) error {
+		commits[commit.Hash] = true
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history of %s: %w", name.Short(), err)
+	}
+	return commits, nil
+}
+
+// fastForward moves the local branch to the given commit and updates the worktree
+func fastForward(repo *git.Repository, worktree *git.Worktree, branchRef plumbing.ReferenceName, hash plumbing.Hash) error {
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(branchRef, hash)); err != nil {
+		return fmt.Errorf("failed to update %s: %w", branchRef.Short(), err)
+	}
+	if err := worktree.Reset(&git.ResetOptions{Commit: hash, Mode: git.HardReset}); err != nil {
+		return fmt.Errorf("failed to update worktree: %w", err)
+	}
+	return nil
+}
+
+// mergeInto applies the remote changes not already made locally and records a merge commit
+func mergeInto(worktree *git.Worktree, local, remote *object.Commit, remoteChanges, localChanges map[string]plumbing.Hash, message string, author object.Signature) error {
+	for _, path := range sortedPaths(remoteChanges) {
+		if _, ok := localChanges[path]; ok {
+			continue // Same change on both sides
+		}
+		if err := checkoutFile(worktree, remote, path); err != nil {
+			return err
+		}
+	}
+
+	author.When = time.Now()
+	_, err := worktree.Commit(message, &git.CommitOptions{
+		Author:            &author,
+		Parents:           []plumbing.Hash{local.Hash, remote.Hash},
+		AllowEmptyCommits: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to commit merge: %w", err)
+	}
+	return nil
+}
+
+// rebaseOnto resets the branch to the remote commit and replays the local commits since base
+func rebaseOnto(repo *git.Repository, worktree *git.Worktree, branchRef plumbing.ReferenceName, base, local, remote *object.Commit) error {
+	var commits []*object.Commit
+	for commit := local; commit.Hash != base.Hash; {
+		commits = append(commits, commit)
+		if commit.NumParents() == 0 {
+			break
+		}
+		parent, err := commit.Parent(0)
+		if err != nil {
+			return fmt.Errorf("failed to read commit history: %w", err)
+		}
+		commit = parent
+	}
+
+	if err := fastForward(repo, worktree, branchRef, remote.Hash); err != nil {
+		return err
+	}
+
+	// Replay oldest first
+	for i := len(commits) - 1; i >= 0; i-- {
+		commit := commits[i]
+		parent := base
+		if commit.NumParents() > 0 {
+			var err error
+			if parent, err = commit.Parent(0); err != nil {
+				return fmt.Errorf("failed to read commit history: %w", err)
+			}
+		}
+
+		changes, err := changedFiles(parent, commit)
+		if err != nil {
+			return err
+		}
+		for _, path := range sortedPaths(changes) {
+			if err := checkoutFile(worktree, commit, path); err != nil {
+				return err
+			}
+		}
+
+		author := commit.Author
+		_, err = worktree.Commit(commit.Message, &git.CommitOptions{
+			Author:            &author,
+			AllowEmptyCommits: true,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to replay commit %s: %w", commit.Hash.String()[:7], err)
+		}
+	}
+	return nil
+}
+
+// changedFiles returns the files that differ between two commits with the blob hash
+// they have in the second one, or the zero hash when they were deleted
+func changedFiles(from, to *object.Commit) (map[string]plumbing.Hash, error) {
+	fromTree, err := from.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tree: %w", err)
+	}
+	toTree, err := to.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tree: %w", err)
+	}
+
+	changes, err := object.DiffTree(fromTree, toTree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff trees: %w", err)
+	}
+
+	files := make(map[string]plumbing.Hash, len(changes))
+	for _, change := range changes {
+		if change.From.Name != "" {
+			files[change.From.Name] = plumbing.ZeroHash
+		}
+		if change.To.Name != "" {
+			files[change.To.Name] = change.To.TreeEntry.Hash
+		}
+	}
+	return files, nil
+}
+
+// conflictingFiles returns the files changed on both sides to different contents
+func conflictingFiles(local, remote map[string]plumbing.Hash) []string {
+	var conflicts []string
+	for path, hash := range local {
+		if remoteHash, ok := remote[path]; ok && remoteHash != hash {
+			conflicts = append(conflicts, path)
+		}
+	}
+	sort.Strings(conflicts)
+	return conflicts
+}
+
+// checkoutFile writes the version of a file from a commit into the worktree and
+// stages it, or removes the file if it does not exist in the commit
+func checkoutFile(worktree *git.Worktree, commit *object.Commit, path string) error {
+	fs := worktree.Filesystem
+
+	file, err := commit.File(path)
+	if errors.Is(err, object.ErrFileNotFound) {
+		if err := fs.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		if _, err := worktree.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to stage removal of %s: %w", path, err)
+		}
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	reader, err := file.Reader()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	mode, err := file.Mode.ToOSFileMode()
+	if err != nil {
+		mode = 0644
+	}
+
+	if err := fs.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+	out, err := fs.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode.Perm())
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if _, err := io.Copy(out, reader); err != nil {
+		_ = out.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	if _, err := worktree.Add(path); err != nil {
+		return fmt.Errorf("failed to stage %s: %w", path, err)
+	}
+	return nil
+}
+
+func sortedPaths(files map[string]plumbing.Hash) []string {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,217 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMachine returns an empty repository on disk with the bare remote as origin
+func newMachine(t *testing.T, remote string) (GitRepository, string) {
+	t.Helper()
+	dir := t.TempDir()
+
+	repo, err := NewRepository(dir)
+	require.NoError(t, err)
+	require.NoError(t, repo.Init("main"))
+	require.NoError(t, repo.SetRemote("origin", remote))
+	return repo, dir
+}
+
+// commitFile writes a file and commits it
+func commitFile(t *testing.T, repo GitRepository, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	require.NoError(t, repo.Add("."))
+	require.NoError(t, repo.Commit("update "+name))
+}
+
+func readFile(t *testing.T, dir, name string) string {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join(dir, name))
+	require.NoError(t, err)
+	return string(content)
+}
+
+// newRemote creates a bare repository and two machines sharing a first commit through it
+func newRemote(t *testing.T) (GitRepository, string, GitRepository, string) {
+	t.Helper()
+	remote := t.TempDir()
+	_, err := git.PlainInit(remote, true)
+	require.NoError(t, err)
+
+	first, firstDir := newMachine(t, remote)
+	commitFile(t, first, firstDir, "shared.txt", "base\n")
+	require.NoError(t, first.Push())
+
+	second, secondDir := newMachine(t, remote)
+	require.NoError(t, second.Pull(PullOptions{}))
+	return first, firstDir, second, secondDir
+}
+
+func TestRepository_Pull_FreshStore(t *testing.T) {
+	_, _, _, secondDir := newRemote(t)
+	assert.Equal(t, "base\n", readFile(t, secondDir, "shared.txt"))
+}
+
+func TestRepository_Pull_FastForward(t *testing.T) {
+	first, firstDir, second, secondDir := newRemote(t)
+
+	commitFile(t, first, firstDir, "shared.txt", "from first\n")
+	require.NoError(t, first.Push())
+
+	require.NoError(t, second.Pull(PullOptions{}))
+	assert.Equal(t, "from first\n", readFile(t, secondDir, "shared.txt"))
+
+	// Pulling again is a no-op
+	assert.NoError(t, second.Pull(PullOptions{}))
+}
+
+func TestRepository_Pull_Diverged(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy PullStrategy
+	}{
+		{name: "merge", strategy: PullMerge},
+		{name: "rebase", strategy: PullRebase},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, firstDir, second, secondDir := newRemote(t)
+
+			commitFile(t, first, firstDir, "first.txt", "first\n")
+			require.NoError(t, first.Push())
+			commitFile(t, second, secondDir, "second.txt", "second\n")
+
+			err := second.Pull(PullOptions{})
+			assert.ErrorIs(t, err, ErrNonFastForward)
+
+			require.NoError(t, second.Pull(PullOptions{Strategy: tt.strategy}))
+			assert.Equal(t, "first\n", readFile(t, secondDir, "first.txt"))
+			assert.Equal(t, "second\n", readFile(t, secondDir, "second.txt"))
+
+			status, err := second.Status()
+			require.NoError(t, err)
+			assert.Empty(t, status)
+
+			// The result can be pushed and pulled back by the first machine
+			require.NoError(t, second.Push())
+			require.NoError(t, first.Pull(PullOptions{}))
+			assert.Equal(t, "second\n", readFile(t, firstDir, "second.txt"))
+		})
+	}
+}
+
+func TestRepository_Pull_Conflict(t *testing.T) {
+	first, firstDir, second, secondDir := newRemote(t)
+
+	commitFile(t, first, firstDir, "shared.txt", "first\n")
+	require.NoError(t, first.Push())
+	commitFile(t, second, secondDir, "shared.txt", "second\n")
+
+	err := second.Pull(PullOptions{Strategy: PullMerge})
+	assert.True(t, IsMergeConflictError(err))
+	assert.Contains(t, err.Error(), "shared.txt")
+
+	// Nothing was touched
+	assert.Equal(t, "second\n", readFile(t, secondDir, "shared.txt"))
+}
+
+func TestRepository_Pull_UncommittedChanges(t *testing.T) {
+	_, _, second, secondDir := newRemote(t)
+
+	require.NoError(t, os.WriteFile(filepath.Join(secondDir, "shared.txt"), []byte("dirty\n"), 0644))
+	assert.ErrorIs(t, second.Pull(PullOptions{}), ErrUncommittedChanges)
+}
+
+func TestRepository_BranchStatus(t *testing.T) {
+	first, firstDir, second, secondDir := newRemote(t)
+
+	status, err := second.BranchStatus()
+	require.NoError(t, err)
+	assert.Equal(t, &BranchStatus{Branch: "main", Upstream: "origin/main"}, status)
+
+	commitFile(t, first, firstDir, "first.txt", "first\n")
+	require.NoError(t, first.Push())
+	commitFile(t, second, secondDir, "second.txt", "second\n")
+	commitFile(t, second, secondDir, "second.txt", "second again\n")
+
+	// The remote refs only move on fetch
+	status, err = second.BranchStatus()
+	require.NoError(t, err)
+	assert.Equal(t, 2, status.Ahead)
+	assert.Equal(t, 0, status.Behind)
+
+	require.NoError(t, second.Fetch())
+	status, err = second.BranchStatus()
+	require.NoError(t, err)
+	assert.Equal(t, 2, status.Ahead)
+	assert.Equal(t, 1, status.Behind)
+}
+
+func TestRepository_BranchStatus_NeverPushed(t *testing.T) {
+	remote := t.TempDir()
+	_, err := git.PlainInit(remote, true)
+	require.NoError(t, err)
+
+	repo, dir := newMachine(t, remote)
+	commitFile(t, repo, dir, "file.txt", "content\n")
+
+	status, err := repo.BranchStatus()
+	require.NoError(t, err)
+	assert.Equal(t, &BranchStatus{Branch: "main", Ahead: 1}, status)
+}
+
+func TestRepository_Fetch_NoRemote(t *testing.T) {
+	repo, err := NewRepository(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, repo.Init("main"))
+
+	assert.True(t, IsNoRemoteError(repo.Fetch()))
+	assert.True(t, IsNoRemoteError(repo.Pull(PullOptions{})))
+
+	_, err = repo.BranchStatus()
+	assert.True(t, IsNoRemoteError(err))
+}
+
+func TestMemoryRepository_Pull(t *testing.T) {
+	remote := t.TempDir()
+	_, err := git.PlainInit(remote, true)
+	require.NoError(t, err)
+
+	first, firstDir := newMachine(t, remote)
+	commitFile(t, first, firstDir, "shared.txt", "base\n")
+	require.NoError(t, first.Push())
+
+	memRepo := NewMemoryRepository("/test").(*MemoryRepository)
+	require.NoError(t, memRepo.Init("main"))
+	require.NoError(t, memRepo.SetRemote("origin", remote))
+	require.NoError(t, memRepo.Pull(PullOptions{}))
+
+	content, err := memRepo.fs.Open("shared.txt")
+	require.NoError(t, err)
+	defer func() { _ = content.Close() }()
+	buf := make([]byte, 16)
+	n, _ := content.Read(buf)
+	assert.Equal(t, "base\n", string(buf[:n]))
+}
+
+func TestParsePullStrategy(t *testing.T) {
+	strategy, err := ParsePullStrategy("")
+	require.NoError(t, err)
+	assert.Equal(t, PullFastForward, strategy)
+
+	strategy, err = ParsePullStrategy("rebase")
+	require.NoError(t, err)
+	assert.Equal(t, PullRebase, strategy)
+
+	_, err = ParsePullStrategy("octopus")
+	assert.Error(t, err)
+}
@@ -195,24 +195,85 @@ func (r *Repository) PushContext(ctx context.Context) error {
 		}
 	}
 
-	// Get the first available remote
+	remoteName, auth, err := r.remoteAuth("push")
+	if err != nil {
+		return err
+	}
+
+	err = r.repo.PushContext(ctx, &git.PushOptions{
+		RemoteName: remoteName,
+		Auth:       auth,
+	})
+	if err != nil {
+		// Check if it's a "no changes" error, which is not actually an error
+		if err == git.NoErrAlreadyUpToDate {
+			return nil
+		}
+		return fmt.Errorf("failed to push: %w", err)
+	}
+
+	return nil
+}
+
+// Fetch downloads objects and refs from the remote repository
+func (r *Repository) Fetch() error {
+	return r.FetchContext(context.Background())
+}
+
+// FetchContext downloads objects and refs from the remote repository with context
+func (r *Repository) FetchContext(ctx context.Context) error {
+	if err := r.openRepository(); err != nil {
+		return err
+	}
+
+	remoteName, auth, err := r.remoteAuth("fetch")
+	if err != nil {
+		return err
+	}
+
+	return fetchRemote(ctx, r.repo, remoteName, auth)
+}
+
+// Pull fetches the remote branch and integrates it into the current branch
+func (r *Repository) Pull(opts PullOptions) error {
+	return r.PullContext(context.Background(), opts)
+}
+
+// PullContext fetches and integrates the remote branch with context
+func (r *Repository) PullContext(ctx context.Context, opts PullOptions) error {
+	if err := r.openRepository(); err != nil {
+		return err
+	}
+
+	remoteName, auth, err := r.remoteAuth("pull")
+	if err != nil {
+		return err
+	}
+
+	return pullRepository(ctx, r.repo, r.workingDir, remoteName, auth, opts, object.Signature{
+		Name:  "Gart",
+		Email: "gart@localhost",
+	})
+}
+
+// remoteAuth returns the name of the first available remote and the authentication for its URL
+func (r *Repository) remoteAuth(op string) (string, transport.AuthMethod, error) {
 	remotes, err := r.repo.Remotes()
 	if err != nil {
-		return fmt.Errorf("failed to get remotes: %w", err)
+		return "", nil, fmt.Errorf("failed to get remotes: %w", err)
 	}
 
 	if len(remotes) == 0 {
-		return &GitError{
-			Op:   "push",
+		return "", nil, &GitError{
+			Op:   op,
 			Path: r.workingDir,
 			Err:  ErrNoRemote,
 		}
 	}
 
 	// Use the first available remote
-	remoteName := remotes[0].Config().Name
 	remoteConfig := remotes[0].Config()
-	
+
 	// Get authentication for the remote URL
 	var auth transport.AuthMethod
 	if len(remoteConfig.URLs) > 0 {
@@ -223,19 +284,55 @@ func (r *Repository) PushContext(ctx context.Context) error {
 		}
 	}
 
-	err = r.repo.PushContext(ctx, &git.PushOptions{
-		RemoteName: remoteName,
-		Auth:       auth,
-	})
-	if err != nil {
-		// Check if it's a "no changes" error, which is not actually an error
-		if err == git.NoErrAlreadyUpToDate {
-			return nil
-		}
-		return fmt.Errorf("failed to push: %w", err)
+	return remoteConfig.Name, auth, nil
+}
+
+// Log returns the commits of all branches touching path, newest first
+func (r *Repository) Log(path string, limit int) ([]CommitInfo, error) {
+	if err := r.openRepository(); err != nil {
+		return nil, err
 	}
+	return logRepository(r.repo, path, limit)
+}
 
-	return nil
+// Show returns the commit a revision resolves to and the files it changed under path
+func (r *Repository) Show(rev, path string) (*CommitInfo, []FileChange, error) {
+	if err := r.openRepository(); err != nil {
+		return nil, nil, err
+	}
+	return showRepository(r.repo, rev, path)
+}
+
+// Diff returns the files under path that differ between two revisions
+func (r *Repository) Diff(fromRev, toRev, path string) ([]FileChange, error) {
+	if err := r.openRepository(); err != nil {
+		return nil, err
+	}
+	return diffRepository(r.repo, fromRev, toRev, path)
+}
+
+// ResolveBranch returns the reference of a branch, preferring a remote branch
+func (r *Repository) ResolveBranch(name string) (string, error) {
+	if err := r.openRepository(); err != nil {
+		return "", err
+	}
+	return resolveBranch(r.repo, name)
+}
+
+// RevisionAt returns the hash of the newest commit of the current branch made at or before at
+func (r *Repository) RevisionAt(at time.Time) (string, error) {
+	if err := r.openRepository(); err != nil {
+		return "", err
+	}
+	return revisionAt(r.repo, at)
+}
+
+// Restore checks out the files under path as they were at a revision and stages them
+func (r *Repository) Restore(rev, path string) (*CommitInfo, error) {
+	if err := r.openRepository(); err != nil {
+		return nil, err
+	}
+	return restoreRepository(r.repo, rev, path)
 }
 
 // Status returns a list of changed files
@@ -262,6 +359,26 @@ func (r *Repository) Status() ([]string, error) {
 	return files, nil
 }
 
+// BranchStatus compares the current branch with its branch on the first remote
+func (r *Repository) BranchStatus() (*BranchStatus, error) {
+	if err := r.openRepository(); err != nil {
+		return nil, err
+	}
+
+	remotes, err := r.repo.Remotes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get remotes: %w", err)
+	}
+	if len(remotes) == 0 {
+		return nil, &GitError{
+			Op:   "status",
+			Path: r.workingDir,
+			Err:  ErrNoRemote,
+		}
+	}
+	return branchStatus(r.repo, remotes[0].Config().Name)
+}
+
 // HasRemote checks if any remote is configured
 func (r *Repository) HasRemote() (bool, error) {
 	if err := r.openRepository(); err != nil {
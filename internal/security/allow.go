@@ -0,0 +1,127 @@
+package security
+
+import (
+	"strings"
+)
+
+const (
+	// allowDirective silences the findings on its own line
+	allowDirective = "gart:allow"
+	// allowNextLineDirective silences the findings on the line below it
+	allowNextLineDirective = "gart:allow-next-line"
+)
+
+// secretTypes are the types reported by the built-in detectors
+var secretTypes = []SecretType{
+	SecretTypeAPIKey, SecretTypeAnthropicKey, SecretTypeGenericAPIKey, SecretTypePassword,
+	SecretTypePrivateKey, SecretTypeToken, SecretTypeAWSKey, SecretTypeGitHubToken,
+	SecretTypeJWT, SecretTypeDatabaseURL, SecretTypeGeneric, SecretTypePII,
+	SecretTypeCreditCard, SecretTypeSSN, SecretTypeEmail, SecretTypeIPAddress,
+	SecretTypeSlackToken, SecretTypeStripeKey, SecretTypeGitLabToken, SecretTypeNPMToken,
+	SecretTypePyPIToken, SecretTypeDockerHubToken, SecretTypeGCPServiceAccount, SecretTypeAzureKey,
+	SecretTypeTwilioKey, SecretTypeSendGridKey, SecretTypeHuggingFaceToken, SecretTypeDiscordToken,
+	SecretTypeTelegramToken,
+}
+
+// allowance is what the inline comments silence on a line
+type allowance struct {
+	all   bool
+	types map[SecretType]bool
+}
+
+func (a *allowance) allows(secretType SecretType) bool {
+	return a.all || a.types[secretType]
+}
+
+// inlineAllowances returns the allowances of the lines with gart:allow comments, keyed by
+// line number. The directive may be followed by secret types separated by spaces or
+// commas, the first other word starts a free text reason. Without types it silences
+// every finding.
+func (d *Detector) inlineAllowances(lines []string) map[int]*allowance {
+	allowances := make(map[int]*allowance)
+	for i, line := range lines {
+		for rest := line; ; {
+			index := strings.Index(rest, allowDirective)
+			if index < 0 {
+				break
+			}
+			rest = rest[index+len(allowDirective):]
+
+			lineNum := i + 1
+			if suffix := strings.TrimPrefix(allowNextLineDirective, allowDirective); strings.HasPrefix(rest, suffix) {
+				rest = rest[len(suffix):]
+				lineNum++
+			}
+			// e.g. gart:allowed is not a directive
+			if rest != "" && !strings.ContainsAny(rest[:1], " \t,:") {
+				continue
+			}
+
+			a, ok := allowances[lineNum]
+			if !ok {
+				a = &allowance{types: make(map[SecretType]bool)}
+				allowances[lineNum] = a
+			}
+			types := d.allowedTypes(rest)
+			if len(types) == 0 {
+				a.all = true
+			}
+			for _, secretType := range types {
+				a.types[secretType] = true
+			}
+		}
+	}
+	return allowances
+}
+
+// allowedTypes returns the secret types listed at the start of the directive arguments
+func (d *Detector) allowedTypes(arguments string) []SecretType {
+	var types []SecretType
+	words := strings.FieldsFunc(arguments, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == ',' || r == ':'
+	})
+	for _, word := range words {
+		secretType := SecretType(word)
+		if !d.isKnownType(secretType) {
+			break
+		}
+		types = append(types, secretType)
+	}
+	return types
+}
+
+func (d *Detector) isKnownType(secretType SecretType) bool {
+	if _, ok := d.patterns[secretType]; ok {
+		return true
+	}
+	for _, rule := range d.rules {
+		if rule.secretType == secretType {
+			return true
+		}
+	}
+	for _, known := range secretTypes {
+		if known == secretType {
+			return true
+		}
+	}
+	return false
+}
+
+// filterAllowed drops the findings silenced by gart:allow comments and counts them
+func (d *Detector) filterAllowed(findings []Finding, lines []string) ([]Finding, int) {
+	allowances := d.inlineAllowances(lines)
+	if len(allowances) == 0 {
+		return findings, 0
+	}
+
+	var kept []Finding
+	allowed := 0
+	for _, finding := range findings {
+		if a, ok := allowances[finding.Location.LineNumber]; ok && a.allows(finding.Type) {
+			allowed++
+			continue
+		}
+		kept = append(kept, finding)
+	}
+	return kept, allowed
+}
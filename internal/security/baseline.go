@@ -0,0 +1,177 @@
+package security
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+)
+
+// BaselineVersion is the version of the baseline file format
+const BaselineVersion = 1
+
+// BaselineEntry is the fingerprint of a reviewed finding. Secrets are only stored hashed.
+type BaselineEntry struct {
+	// File is the path of the file, relative to the home directory as ~/... when below it
+	File string `toml:"file"`
+	Type string `toml:"type"`
+	// SecretHash is the SHA-256 of the raw value
+	SecretHash string `toml:"secret_hash"`
+	// LineHash is the SHA-256 of the trimmed line holding the value
+	LineHash string `toml:"line_hash,omitempty"`
+}
+
+// Baseline holds the findings accepted after review, which scans no longer report
+type Baseline struct {
+	Version  int             `toml:"version"`
+	Findings []BaselineEntry `toml:"findings"`
+
+	path  string
+	index map[BaselineEntry]bool
+}
+
+// LoadBaseline reads the baseline at path. A missing file yields an empty baseline.
+func LoadBaseline(path string) (*Baseline, error) {
+	b := &Baseline{Version: BaselineVersion, path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("error reading baseline: %w", err)
+	}
+	if err == nil {
+		if err := toml.Unmarshal(data, b); err != nil {
+			return nil, fmt.Errorf("error parsing baseline %s: %w", path, err)
+		}
+		if b.Version > BaselineVersion {
+			return nil, fmt.Errorf("baseline %s has version %d, this gart supports up to %d", path, b.Version, BaselineVersion)
+		}
+	}
+
+	b.reindex()
+	return b, nil
+}
+
+// Path returns the file the baseline is loaded from and saved to
+func (b *Baseline) Path() string {
+	return b.path
+}
+
+// Contains reports whether the finding was accepted
+func (b *Baseline) Contains(finding Finding) bool {
+	return b.index[Fingerprint(finding)]
+}
+
+// Update replaces the accepted findings of the scanned files with the findings of the
+// report, already accepted or not. Entries of files outside the report are kept. It
+// returns the number of findings accepted for the scanned files.
+func (b *Baseline) Update(report *ScanReport) int {
+	scanned := make(map[string]bool)
+	for _, result := range report.Results {
+		scanned[baselineFile(result.FilePath)] = true
+	}
+
+	var findings []BaselineEntry
+	for _, entry := range b.Findings {
+		if !scanned[entry.File] {
+			findings = append(findings, entry)
+		}
+	}
+
+	accepted := 0
+	added := make(map[BaselineEntry]bool)
+	for _, result := range report.Results {
+		for _, group := range [][]Finding{result.Accepted, result.Findings} {
+			for _, finding := range group {
+				if finding.Location.FilePath == "" {
+					finding.Location.FilePath = result.FilePath
+				}
+				accepted++
+
+				// Findings of several detectors for the same value share an entry
+				entry := Fingerprint(finding)
+				if !added[entry] {
+					added[entry] = true
+					findings = append(findings, entry)
+				}
+			}
+		}
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].File != findings[j].File {
+			return findings[i].File < findings[j].File
+		}
+		if findings[i].Type != findings[j].Type {
+			return findings[i].Type < findings[j].Type
+		}
+		return findings[i].SecretHash < findings[j].SecretHash
+	})
+
+	b.Version = BaselineVersion
+	b.Findings = findings
+	b.reindex()
+	return accepted
+}
+
+// Save writes the baseline back to the file it was loaded from
+func (b *Baseline) Save() error {
+	data, err := toml.Marshal(b)
+	if err != nil {
+		return fmt.Errorf("error encoding baseline: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(b.path), 0755); err != nil {
+		return fmt.Errorf("error creating baseline directory: %w", err)
+	}
+
+	// Write to a temporary file first so an interrupted save never truncates the baseline
+	tmp := b.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("error writing baseline: %w", err)
+	}
+	return os.Rename(tmp, b.path)
+}
+
+func (b *Baseline) reindex() {
+	b.index = make(map[BaselineEntry]bool, len(b.Findings))
+	for _, entry := range b.Findings {
+		b.index[entry] = true
+	}
+}
+
+// Fingerprint identifies a finding by its file, type, value and line, but not by its
+// line number so that edits elsewhere in the file keep it accepted
+func Fingerprint(finding Finding) BaselineEntry {
+	entry := BaselineEntry{
+		File:       baselineFile(finding.Location.FilePath),
+		Type:       string(finding.Type),
+		SecretHash: sha256Hex(finding.RawValue),
+	}
+	if line := strings.TrimSpace(finding.Location.LineText); line != "" {
+		entry.LineHash = sha256Hex(line)
+	}
+	return entry
+}
+
+// baselineFile makes paths below the home directory portable between hosts
+func baselineFile(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		if rel, err := filepath.Rel(home, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return filepath.ToSlash(filepath.Join("~", rel))
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+func sha256Hex(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
@@ -10,6 +10,8 @@ type SecurityConfig struct {
 	Sensitivity     SensitivityLevel `toml:"sensitivity"`
 	FailOnSecrets   bool             `toml:"fail_on_secrets"`
 	Interactive     bool             `toml:"interactive"`
+	// Baseline is the file of reviewed findings, next to the config file when empty
+	Baseline string `toml:"baseline,omitempty"`
 
 	// Pattern exclusion configuration
 	PatternConfig PatternConfig `toml:"pattern_config,omitempty"`
@@ -19,6 +21,9 @@ type SecurityConfig struct {
 
 	// Allowlist configuration
 	Allowlist AllowlistConfig `toml:"allowlist,omitempty"`
+
+	// User-defined detection rules
+	Rules []Rule `toml:"rules,omitempty"`
 }
 
 type PatternConfig struct {
@@ -87,6 +92,9 @@ func (sc *SecurityConfig) Merge(other *SecurityConfig) {
 	if other.Interactive != sc.Interactive {
 		sc.Interactive = other.Interactive
 	}
+	if other.Baseline != "" {
+		sc.Baseline = other.Baseline
+	}
 
 	// Merge pattern configs
 	if len(other.PatternConfig.Critical) > 0 {
@@ -129,6 +137,11 @@ func (sc *SecurityConfig) Merge(other *SecurityConfig) {
 	if len(other.Allowlist.Files) > 0 {
 		sc.Allowlist.Files = append(sc.Allowlist.Files, other.Allowlist.Files...)
 	}
+
+	// Merge detection rules
+	if len(other.Rules) > 0 {
+		sc.Rules = append(sc.Rules, other.Rules...)
+	}
 }
 
 // Validate checks if the security configuration is valid
@@ -168,7 +181,7 @@ func (sc *SecurityConfig) Validate() error {
 		return &ValidationError{Field: "min_secret_length", Message: "must be positive"}
 	}
 
-	return nil
+	return sc.ValidateRules()
 }
 
 // IsFileAllowed checks if a file is in the allowlist
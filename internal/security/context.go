@@ -33,6 +33,11 @@ func NewSecurityContext(config *SecurityConfig) *SecurityContext {
 	}
 }
 
+// SetBaseline leaves the findings accepted in the baseline out of the scan reports
+func (sc *SecurityContext) SetBaseline(baseline *Baseline) {
+	sc.scanner.SetBaseline(baseline)
+}
+
 // ScanPath scans a file or directory for security issues before sync
 func (sc *SecurityContext) ScanPath(path string, ignores []string) (*ScanReport, error) {
 	if !sc.config.Enabled {
@@ -67,12 +72,14 @@ func (sc *SecurityContext) ScanPath(path string, ignores []string) (*ScanReport,
 		}
 
 		report := &ScanReport{
-			Results:       []ScanResult{*result},
-			TotalFiles:    1,
-			ScannedFiles:  1,
-			SkippedFiles:  0,
-			TotalFindings: len(result.Findings),
-			HighestRisk:   result.Risk,
+			Results:          []ScanResult{*result},
+			TotalFiles:       1,
+			ScannedFiles:     1,
+			SkippedFiles:     0,
+			TotalFindings:    len(result.Findings),
+			HighestRisk:      result.Risk,
+			AcceptedFindings: len(result.Accepted),
+			AllowedFindings:  result.Allowed,
 		}
 
 		return report, nil
@@ -118,6 +125,27 @@ func (sc *SecurityContext) InteractivePrompt(report *ScanReport) (bool, bool, er
 	return sc.getUserDecision(report)
 }
 
+// PolicyDecision answers the security prompt with a non-interactive prompt policy.
+// Returns: proceed, the files with findings to leave out of the operation, error
+func (sc *SecurityContext) PolicyDecision(report *ScanReport, policy system.PromptPolicy) (bool, []string, error) {
+	if report.TotalFindings == 0 {
+		return true, nil, nil
+	}
+
+	switch policy {
+	case system.PromptSkip:
+		return true, report.FlaggedFiles(), nil
+	case system.PromptProceed:
+		// Findings blocking a sync without prompting still block it
+		if proceed, msg := sc.ShouldProceed(report); !proceed {
+			return false, nil, fmt.Errorf("%s", msg)
+		}
+		return true, nil, nil
+	default:
+		return false, nil, fmt.Errorf("%d security finding(s) need a decision: %w", report.TotalFindings, system.ErrInputRequired)
+	}
+}
+
 func (sc *SecurityContext) getUserDecision(report *ScanReport) (bool, bool, error) {
 	reader := bufio.NewReader(os.Stdin)
 
@@ -0,0 +1,48 @@
+package security
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bnema/gart/internal/system"
+)
+
+func TestSecurityContext_PolicyDecision(t *testing.T) {
+	report := &ScanReport{
+		Results: []ScanResult{
+			{FilePath: "/home/user/.config/app/clean.conf"},
+			{FilePath: "/home/user/.config/app/token.conf", Findings: []Finding{{Type: "api_key"}}, Risk: RiskLevelHigh},
+		},
+		TotalFindings: 1,
+		HighestRisk:   RiskLevelHigh,
+	}
+
+	config := DefaultSecurityConfig()
+	config.FailOnSecrets = false
+	sc := NewSecurityContext(config)
+
+	proceed, skipFiles, err := sc.PolicyDecision(report, system.PromptFail)
+	if proceed || !errors.Is(err, system.ErrInputRequired) {
+		t.Errorf("fail policy: expected ErrInputRequired, got proceed=%v err=%v", proceed, err)
+	}
+
+	proceed, skipFiles, err = sc.PolicyDecision(report, system.PromptSkip)
+	if !proceed || err != nil {
+		t.Fatalf("skip policy: expected to proceed, got proceed=%v err=%v", proceed, err)
+	}
+	if len(skipFiles) != 1 || skipFiles[0] != "/home/user/.config/app/token.conf" {
+		t.Errorf("skip policy: expected the flagged file to be skipped, got %v", skipFiles)
+	}
+
+	proceed, skipFiles, err = sc.PolicyDecision(report, system.PromptProceed)
+	if !proceed || err != nil || len(skipFiles) != 0 {
+		t.Errorf("proceed policy: expected to proceed, got proceed=%v files=%v err=%v", proceed, skipFiles, err)
+	}
+
+	// Findings blocking a sync without prompting still block it
+	report.HighestRisk = RiskLevelCritical
+	proceed, _, err = sc.PolicyDecision(report, system.PromptProceed)
+	if proceed || err == nil {
+		t.Errorf("proceed policy: expected critical findings to block, got proceed=%v err=%v", proceed, err)
+	}
+}
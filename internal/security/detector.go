@@ -12,6 +12,8 @@ type Detector struct {
 	entropyAnalyzer *EntropyAnalyzer
 	patterns        map[SecretType]*regexp.Regexp
 	contextKeywords []string
+	// rules are the valid user-defined rules of the config
+	rules []*compiledRule
 }
 
 type SensitivityLevel string
@@ -37,6 +39,13 @@ func NewDetector(config *SecurityConfig) *Detector {
 	}
 
 	detector.compilePatterns()
+	if config != nil {
+		for _, rule := range config.Rules {
+			if compiled, err := rule.compile(); err == nil {
+				detector.rules = append(detector.rules, compiled)
+			}
+		}
+	}
 	return detector
 }
 
@@ -83,6 +92,45 @@ func (d *Detector) compilePatterns() {
 
 		// Social Security Numbers
 		SecretTypeSSN: `\b\d{3}-\d{2}-\d{4}\b`,
+
+		// Slack bot, user and app tokens, and incoming webhooks
+		SecretTypeSlackToken: `(xox[abposr]-[0-9A-Za-z-]{10,}|xapp-\d-[A-Z0-9]+-\d+-[a-z0-9]+|https://hooks\.slack\.com/services/T[A-Z0-9]+/B[A-Z0-9]+/[A-Za-z0-9]{24})`,
+
+		// Stripe live secret and restricted keys, and webhook signing secrets
+		SecretTypeStripeKey: `\b([rs]k_live_[0-9a-zA-Z]{24,99}|whsec_[0-9a-zA-Z]{32,})\b`,
+
+		// GitLab personal, deploy, runner, pipeline trigger and service account tokens
+		SecretTypeGitLabToken: `\bgl(?:pat|dt|rt|ptt|soat)-[0-9A-Za-z_-]{20,}`,
+
+		// npm granular and automation tokens
+		SecretTypeNPMToken: `\bnpm_[A-Za-z0-9]{36}\b`,
+
+		// PyPI and TestPyPI upload tokens, macaroons for pypi.org or test.pypi.org
+		SecretTypePyPIToken: `pypi-(?:AgEIcHlwaS5vcmc|AgENdGVzdC5weXBpLm9yZw)[A-Za-z0-9_-]{50,}`,
+
+		// Docker Hub personal and organization access tokens
+		SecretTypeDockerHubToken: `\bdckr_(?:pat|oat)_[A-Za-z0-9_-]{27,}`,
+
+		// Google Cloud service account key files
+		SecretTypeGCPServiceAccount: `"private_key_id"\s*:\s*"(?P<secret>[a-f0-9]{40})"`,
+
+		// Azure storage and service bus keys of connection strings, and Entra ID client secrets
+		SecretTypeAzureKey: `(?:AccountKey|SharedAccessKey)=(?P<secret>[A-Za-z0-9+/]{86}==|[A-Za-z0-9+/]{43}=)|(?P<secret>\b[A-Za-z0-9_~.-]{3}\dQ~[A-Za-z0-9_~.-]{31,34})`,
+
+		// Twilio API keys
+		SecretTypeTwilioKey: `\bSK[0-9a-fA-F]{32}\b`,
+
+		// SendGrid API keys
+		SecretTypeSendGridKey: `\bSG\.[A-Za-z0-9_-]{22}\.[A-Za-z0-9_-]{43}`,
+
+		// Hugging Face access tokens
+		SecretTypeHuggingFaceToken: `\bhf_[A-Za-z0-9]{34}\b`,
+
+		// Discord bot tokens
+		SecretTypeDiscordToken: `\b[MNO][A-Za-z0-9_-]{23,25}\.[A-Za-z0-9_-]{6}\.[A-Za-z0-9_-]{27,38}`,
+
+		// Telegram bot tokens
+		SecretTypeTelegramToken: `\b\d{8,10}:AA[A-Za-z0-9_-]{33}`,
 	}
 
 	for secretType, pattern := range secretPatterns {
@@ -103,7 +151,15 @@ func getContextKeywords() []string {
 	}
 }
 
+// DetectSecrets returns the secrets found in content, except those silenced by gart:allow
+// and gart:allow-next-line comments
 func (d *Detector) DetectSecrets(content []byte, filePath string) []Finding {
+	findings, _ := d.detectSecrets(content, filePath)
+	return findings
+}
+
+// detectSecrets implements DetectSecrets and counts the findings silenced by comments
+func (d *Detector) detectSecrets(content []byte, filePath string) ([]Finding, int) {
 	var findings []Finding
 
 	text := string(content)
@@ -114,7 +170,7 @@ func (d *Detector) DetectSecrets(content []byte, filePath string) []Finding {
 		matches := pattern.FindAllStringSubmatch(text, -1)
 		for _, match := range matches {
 			if len(match) > 0 {
-				finding := d.createFinding(secretType, match[0], text, filePath)
+				finding := d.createFinding(secretType, patternValue(pattern, match), text, filePath)
 				if finding != nil {
 					findings = append(findings, *finding)
 				}
@@ -122,6 +178,15 @@ func (d *Detector) DetectSecrets(content []byte, filePath string) []Finding {
 		}
 	}
 
+	// User-defined rules
+	for _, rule := range d.rules {
+		for _, match := range rule.match(text, lines, filePath) {
+			if match.Skipped == "" {
+				findings = append(findings, match.Finding)
+			}
+		}
+	}
+
 	// Entropy-based detection
 	for lineNum, line := range lines {
 		entropyFindings := d.detectEntropySecrets(line, lineNum+1, filePath)
@@ -135,7 +200,17 @@ func (d *Detector) DetectSecrets(content []byte, filePath string) []Finding {
 	// Remove duplicates and merge similar findings
 	findings = d.deduplicateFindings(findings)
 
-	return findings
+	return d.filterAllowed(findings, lines)
+}
+
+// patternValue returns the first group named secret of a pattern match, or the whole match
+func patternValue(pattern *regexp.Regexp, match []string) string {
+	for i, name := range pattern.SubexpNames() {
+		if name == "secret" && match[i] != "" {
+			return match[i]
+		}
+	}
+	return match[0]
 }
 
 func (d *Detector) createFinding(secretType SecretType, value, text, filePath string) *Finding {
@@ -144,6 +219,11 @@ func (d *Detector) createFinding(secretType SecretType, value, text, filePath st
 		return nil
 	}
 
+	// Skip tokens failing the checksum or structure of their format
+	if validate, ok := tokenValidators[secretType]; ok && !validate(value) {
+		return nil
+	}
+
 	// Find line number and context
 	lines := strings.Split(text, "\n")
 	lineNum := 1
@@ -768,6 +848,11 @@ func (d *Detector) calculatePatternConfidence(secretType SecretType, value, cont
 	case SecretTypePrivateKey, SecretTypeAWSKey, SecretTypeAnthropicKey, 
 		 SecretTypeAPIKey, SecretTypeGenericAPIKey, SecretTypeGitHubToken, SecretTypeJWT:
 		return 1.0  // 100% confidence for known secret patterns
+	case SecretTypeSlackToken, SecretTypeStripeKey, SecretTypeGitLabToken, SecretTypeNPMToken,
+		SecretTypePyPIToken, SecretTypeDockerHubToken, SecretTypeGCPServiceAccount, SecretTypeAzureKey,
+		SecretTypeTwilioKey, SecretTypeSendGridKey, SecretTypeHuggingFaceToken, SecretTypeDiscordToken,
+		SecretTypeTelegramToken:
+		return 1.0 // Prefixed or validated token formats
 	case SecretTypeDatabaseURL:
 		return 0.95 // Very high confidence for DB URLs
 	case SecretTypeCreditCard:
@@ -912,6 +997,20 @@ func (d *Detector) getRiskLevel(secretType SecretType, confidence float64) RiskL
 		SecretTypeSSN:           RiskLevelLow,
 		SecretTypeEmail:         RiskLevelLow,
 		SecretTypeIPAddress:     RiskLevelLow,
+
+		SecretTypeSlackToken:        RiskLevelCritical,
+		SecretTypeStripeKey:         RiskLevelCritical,
+		SecretTypeGitLabToken:       RiskLevelCritical,
+		SecretTypeNPMToken:          RiskLevelCritical,
+		SecretTypePyPIToken:         RiskLevelCritical,
+		SecretTypeDockerHubToken:    RiskLevelCritical,
+		SecretTypeGCPServiceAccount: RiskLevelCritical,
+		SecretTypeAzureKey:          RiskLevelCritical,
+		SecretTypeTwilioKey:         RiskLevelCritical,
+		SecretTypeSendGridKey:       RiskLevelCritical,
+		SecretTypeHuggingFaceToken:  RiskLevelCritical,
+		SecretTypeDiscordToken:      RiskLevelCritical,
+		SecretTypeTelegramToken:     RiskLevelCritical,
 	}
 
 	risk := baseRisk[secretType]
@@ -1087,6 +1186,21 @@ func (d *Detector) isMoreSpecific(type1, type2 SecretType) bool {
 		SecretTypeAnthropicKey:  8,
 		SecretTypeAPIKey:        8,
 		SecretTypePrivateKey:    9,
+
+		// Provider token formats are more precise than the keyword based types
+		SecretTypeSlackToken:        9,
+		SecretTypeStripeKey:         9,
+		SecretTypeGitLabToken:       9,
+		SecretTypeNPMToken:          9,
+		SecretTypePyPIToken:         9,
+		SecretTypeDockerHubToken:    9,
+		SecretTypeGCPServiceAccount: 9,
+		SecretTypeAzureKey:          9,
+		SecretTypeTwilioKey:         9,
+		SecretTypeSendGridKey:       9,
+		SecretTypeHuggingFaceToken:  9,
+		SecretTypeDiscordToken:      9,
+		SecretTypeTelegramToken:     9,
 	}
 
 	spec1, ok1 := specificity[type1]
@@ -1099,6 +1213,16 @@ func (d *Detector) isMoreSpecific(type1, type2 SecretType) bool {
 		spec2 = 0
 	}
 
+	// User-defined rules describe a format precisely
+	for _, rule := range d.rules {
+		if rule.secretType == type1 {
+			spec1 = 10
+		}
+		if rule.secretType == type2 {
+			spec2 = 10
+		}
+	}
+
 	return spec1 > spec2
 }
 
@@ -0,0 +1,222 @@
+package security
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Rule is a user-defined secret detector, run along the built-in ones
+type Rule struct {
+	ID string `toml:"id"`
+	// Regex matches the secret. The group named secret, or else the first group, holds
+	// the value when the regex has groups.
+	Regex string `toml:"regex"`
+	// SecretType is reported for the findings, the rule ID when empty
+	SecretType string `toml:"secret_type,omitempty"`
+	// Risk is the risk level of the findings, high when empty
+	Risk string `toml:"risk,omitempty"`
+	// Keywords restrict the rule to lines holding one of them, ignoring case
+	Keywords []string `toml:"keywords,omitempty"`
+	// MinEntropy is the Shannon entropy a value needs to be reported
+	MinEntropy float64 `toml:"min_entropy,omitempty"`
+	// Allowlist holds regexes of values that are never reported
+	Allowlist []string `toml:"allowlist,omitempty"`
+}
+
+// RuleMatch is a match of a rule, reported as a finding unless Skipped explains why not
+type RuleMatch struct {
+	Finding Finding
+	Skipped string
+}
+
+type compiledRule struct {
+	Rule
+	regex      *regexp.Regexp
+	group      int
+	secretType SecretType
+	risk       RiskLevel
+	keywords   []string
+	allowlist  []*regexp.Regexp
+}
+
+// compile checks the rule and compiles its regexes
+func (r Rule) compile() (*compiledRule, error) {
+	if r.ID == "" || strings.ContainsAny(r.ID, " \t\n") {
+		return nil, fmt.Errorf("id must be set and have no spaces")
+	}
+	if strings.ContainsAny(r.SecretType, " \t\n,:") {
+		return nil, fmt.Errorf("secret_type must have no spaces, commas or colons")
+	}
+
+	regex, err := regexp.Compile(r.Regex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex: %w", err)
+	}
+	if r.Regex == "" || regex.MatchString("") {
+		return nil, fmt.Errorf("regex must not match an empty string")
+	}
+
+	compiled := &compiledRule{Rule: r, regex: regex, secretType: SecretType(r.SecretType), risk: RiskLevelHigh}
+	if compiled.secretType == "" {
+		compiled.secretType = SecretType(r.ID)
+	}
+	if index := regex.SubexpIndex("secret"); index > 0 {
+		compiled.group = index
+	} else if regex.NumSubexp() > 0 {
+		compiled.group = 1
+	}
+
+	if r.Risk != "" {
+		if compiled.risk, err = ParseRiskLevel(r.Risk); err != nil {
+			return nil, err
+		}
+		if compiled.risk == RiskLevelNone {
+			return nil, fmt.Errorf("risk must be one of: low, medium, high, critical")
+		}
+	}
+	if r.MinEntropy < 0 || r.MinEntropy > 8 {
+		return nil, fmt.Errorf("min_entropy must be between 0 and 8")
+	}
+	for _, keyword := range r.Keywords {
+		compiled.keywords = append(compiled.keywords, strings.ToLower(keyword))
+	}
+	for _, pattern := range r.Allowlist {
+		allow, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowlist regex %q: %w", pattern, err)
+		}
+		compiled.allowlist = append(compiled.allowlist, allow)
+	}
+	return compiled, nil
+}
+
+// match returns every match of the rule in text, lines being text split by line
+func (r *compiledRule) match(text string, lines []string, filePath string) []RuleMatch {
+	var matches []RuleMatch
+	for _, indexes := range r.regex.FindAllStringSubmatchIndex(text, -1) {
+		start, end := indexes[2*r.group], indexes[2*r.group+1]
+		if start < 0 || start == end {
+			continue
+		}
+		value := text[start:end]
+		lineNum := strings.Count(text[:start], "\n") + 1
+		lineText := lines[lineNum-1]
+
+		match := RuleMatch{Finding: Finding{
+			Type:       r.secretType,
+			RawValue:   value,
+			Value:      redactValue(value),
+			Location:   Location{FilePath: filePath, LineNumber: lineNum, LineText: lineText},
+			Confidence: 1.0,
+			Context:    lineText,
+			Reasons:    []string{"rule " + r.ID},
+			RiskLevel:  r.risk,
+		}}
+		match.Skipped = r.skipReason(value, lineText)
+		matches = append(matches, match)
+	}
+	return matches
+}
+
+func (r *compiledRule) skipReason(value, line string) string {
+	if len(r.keywords) > 0 {
+		lower := strings.ToLower(line)
+		found := false
+		for _, keyword := range r.keywords {
+			if strings.Contains(lower, keyword) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return "no keyword on the line"
+		}
+	}
+	if entropy := CalculateEntropy(value); entropy < r.MinEntropy {
+		return fmt.Sprintf("entropy %.2f below %.2f", entropy, r.MinEntropy)
+	}
+	for _, allow := range r.allowlist {
+		if allow.MatchString(value) {
+			return "allowlisted by " + allow.String()
+		}
+	}
+	return ""
+}
+
+// ValidateRules checks the user-defined rules have a unique ID and valid settings.
+// Validate also checks them, invalid rules are left out of scans.
+func (sc *SecurityConfig) ValidateRules() error {
+	ids := make(map[string]bool)
+	for i, rule := range sc.Rules {
+		field := fmt.Sprintf("rules[%d]", i)
+		if rule.ID != "" {
+			field = fmt.Sprintf("rules.%s", rule.ID)
+		}
+		if _, err := rule.compile(); err != nil {
+			return &ValidationError{Field: field, Message: err.Error()}
+		}
+		if ids[rule.ID] {
+			return &ValidationError{Field: field, Message: "duplicate rule id"}
+		}
+		ids[rule.ID] = true
+	}
+	return nil
+}
+
+// FindRule returns the user-defined rule with the given ID
+func (sc *SecurityConfig) FindRule(id string) (Rule, bool) {
+	for _, rule := range sc.Rules {
+		if rule.ID == id {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// TestContent runs the rule alone on content and returns every match, including those
+// left out by the keywords, the entropy minimum or the allowlist
+func (r Rule) TestContent(content []byte, filePath string) ([]RuleMatch, error) {
+	compiled, err := r.compile()
+	if err != nil {
+		return nil, fmt.Errorf("invalid rule %s: %w", r.ID, err)
+	}
+	text := string(content)
+	return compiled.match(text, strings.Split(text, "\n"), filePath), nil
+}
+
+// TestPath runs the rule alone on the file or directory at path, skipping the ignored
+// and binary files like a scan
+func (r Rule) TestPath(path string, ignores []string) ([]RuleMatch, error) {
+	compiled, err := r.compile()
+	if err != nil {
+		return nil, fmt.Errorf("invalid rule %s: %w", r.ID, err)
+	}
+
+	var matches []RuleMatch
+	err = filepath.Walk(path, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" || info.Name() == ".github" || (filePath != path && isIgnored(path, filePath, ignores)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if isIgnored(path, filePath, ignores) {
+			return nil
+		}
+
+		content, err := os.ReadFile(filePath)
+		if err != nil || isBinary(content) {
+			return nil
+		}
+		text := string(content)
+		matches = append(matches, compiled.match(text, strings.Split(text, "\n"), filePath)...)
+		return nil
+	})
+	return matches, err
+}
@@ -0,0 +1,155 @@
+package security
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const acmeToken = "acme_live_Zx8Qp2Lm7Rt4Vn9Kc3Hs6Jd1Wf5Gb0Ya"
+
+func acmeRule() Rule {
+	return Rule{
+		ID:         "acme_live",
+		Regex:      `acme_live_[A-Za-z0-9]{32}`,
+		SecretType: "acme_token",
+		Risk:       "critical",
+	}
+}
+
+func TestSecurityConfig_ValidateRules(t *testing.T) {
+	tests := []struct {
+		name       string
+		rules      []Rule
+		errorField string
+	}{
+		{"valid rule", []Rule{acmeRule()}, ""},
+		{"missing id", []Rule{{Regex: `acme_[a-z]+`}}, "rules[0]"},
+		{"invalid regex", []Rule{{ID: "acme", Regex: `acme_(`}}, "rules.acme"},
+		{"empty match", []Rule{{ID: "acme", Regex: `a*`}}, "rules.acme"},
+		{"invalid risk", []Rule{{ID: "acme", Regex: `acme_[a-z]+`, Risk: "severe"}}, "rules.acme"},
+		{"no risk", []Rule{{ID: "acme", Regex: `acme_[a-z]+`, Risk: "none"}}, "rules.acme"},
+		{"invalid entropy", []Rule{{ID: "acme", Regex: `acme_[a-z]+`, MinEntropy: 9}}, "rules.acme"},
+		{"invalid allowlist", []Rule{{ID: "acme", Regex: `acme_[a-z]+`, Allowlist: []string{`[`}}}, "rules.acme"},
+		{"duplicate id", []Rule{acmeRule(), acmeRule()}, "rules.acme_live"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultSecurityConfig()
+			config.Rules = tt.rules
+
+			err := config.Validate()
+			if tt.errorField == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+			validationErr, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("Validate() expected ValidationError, got %v", err)
+			}
+			if validationErr.Field != tt.errorField {
+				t.Errorf("Validate() error field = %s, want %s", validationErr.Field, tt.errorField)
+			}
+		})
+	}
+}
+
+func TestDetector_Rules(t *testing.T) {
+	config := DefaultSecurityConfig()
+	config.Rules = []Rule{acmeRule()}
+	detector := NewDetector(config)
+
+	findings := detector.DetectSecrets([]byte("# billing\nACME_KEY="+acmeToken+"\n"), "acme.env")
+	var found *Finding
+	for i := range findings {
+		if findings[i].Type == "acme_token" {
+			found = &findings[i]
+		}
+	}
+	if found == nil {
+		t.Fatalf("custom rule finding missing: %+v", findings)
+	}
+	if found.RawValue != acmeToken || found.RiskLevel != RiskLevelCritical || found.Location.LineNumber != 2 {
+		t.Errorf("unexpected finding: %s risk %s line %d", found.RawValue, found.RiskLevel, found.Location.LineNumber)
+	}
+
+	// Rule types can scope gart:allow comments
+	findings = detector.DetectSecrets([]byte("ACME_KEY="+acmeToken+" # gart:allow acme_token\n"), "acme.env")
+	for _, finding := range findings {
+		if finding.Type == "acme_token" {
+			t.Error("gart:allow acme_token did not silence the finding")
+		}
+	}
+}
+
+func TestRule_TestContent(t *testing.T) {
+	rule := Rule{
+		ID:         "acme",
+		Regex:      `key\s*=\s*(?P<secret>acme_\w+)`,
+		Keywords:   []string{"TOKEN"},
+		MinEntropy: 3,
+		Allowlist:  []string{`^acme_example`},
+	}
+	content := strings.Join([]string{
+		"# acme tokens",
+		"key = " + acmeToken + " # token",
+		"key = acme_aaaaaaaaaaaaaaaa # token",
+		"key = acme_exampleZx8Qp2Lm7Rt4Vn9K # token",
+		"key = " + acmeToken,
+	}, "\n")
+
+	matches, err := rule.TestContent([]byte(content), "-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		line    int
+		value   string
+		skipped string
+	}{
+		{2, acmeToken, ""},
+		{3, "acme_aaaaaaaaaaaaaaaa", "entropy"},
+		{4, "acme_exampleZx8Qp2Lm7Rt4Vn9K", "allowlisted"},
+		{5, acmeToken, "keyword"},
+	}
+	if len(matches) != len(want) {
+		t.Fatalf("got %d matches, want %d", len(matches), len(want))
+	}
+	for i, w := range want {
+		match := matches[i]
+		if match.Finding.Location.LineNumber != w.line || match.Finding.RawValue != w.value {
+			t.Errorf("match %d = line %d %q, want line %d %q", i, match.Finding.Location.LineNumber, match.Finding.RawValue, w.line, w.value)
+		}
+		if (w.skipped == "") != (match.Skipped == "") || !strings.Contains(match.Skipped, w.skipped) {
+			t.Errorf("match %d skipped = %q, want %q", i, match.Skipped, w.skipped)
+		}
+	}
+	// The secret type defaults to the rule ID
+	if matches[0].Finding.Type != "acme" || matches[0].Finding.RiskLevel != RiskLevelHigh {
+		t.Errorf("got type %s risk %s, want acme HIGH", matches[0].Finding.Type, matches[0].Finding.RiskLevel)
+	}
+}
+
+func TestRule_TestPath(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"acme.env":   "ACME_KEY=" + acmeToken + "\n",
+		"ignored.md": "ACME_KEY=" + acmeToken + "\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	matches, err := acmeRule().TestPath(dir, []string{"*.md"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 || matches[0].Finding.Location.FilePath != filepath.Join(dir, "acme.env") {
+		t.Errorf("unexpected matches: %+v", matches)
+	}
+}
@@ -0,0 +1,188 @@
+package security
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SARIFVersion is the version of the SARIF reports written by WriteSARIF
+const SARIFVersion = "2.1.0"
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID     string          `json:"ruleId"`
+	RuleIndex  int             `json:"ruleIndex"`
+	Level      string          `json:"level"`
+	Message    sarifMessage    `json:"message"`
+	Locations  []sarifLocation `json:"locations"`
+	Properties sarifProperties `json:"properties"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+type sarifProperties struct {
+	Risk       string  `json:"risk"`
+	Confidence float64 `json:"confidence"`
+}
+
+// WriteSARIF writes the findings of the report as a SARIF 2.1.0 log. Files below baseDir
+// are referenced relative to it, other files by absolute file URI. Finding values are
+// always redacted.
+func (r *ScanReport) WriteSARIF(w io.Writer, toolVersion, baseDir string) error {
+	// One rule per secret type, in a stable order
+	var ruleIDs []string
+	ruleIndex := make(map[string]int)
+	for _, result := range r.Results {
+		for _, finding := range result.Findings {
+			id := string(finding.Type)
+			if _, ok := ruleIndex[id]; !ok {
+				ruleIndex[id] = 0
+				ruleIDs = append(ruleIDs, id)
+			}
+		}
+	}
+	sort.Strings(ruleIDs)
+
+	rules := make([]sarifRule, 0, len(ruleIDs))
+	for i, id := range ruleIDs {
+		ruleIndex[id] = i
+		rules = append(rules, sarifRule{
+			ID:               id,
+			ShortDescription: sarifMessage{Text: "Possible " + strings.ReplaceAll(id, "_", " ")},
+		})
+	}
+
+	results := []sarifResult{}
+	for _, result := range r.Results {
+		for _, finding := range result.Findings {
+			file := finding.Location.FilePath
+			if file == "" {
+				file = result.FilePath
+			}
+
+			location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: sarifURI(file, baseDir)},
+			}}
+			if finding.Location.LineNumber > 0 {
+				location.PhysicalLocation.Region = &sarifRegion{StartLine: finding.Location.LineNumber}
+			}
+
+			value := finding.Value
+			if finding.RawValue != "" {
+				value = finding.Redact()
+			}
+			message := fmt.Sprintf("Possible %s (%s risk, %.0f%% confidence)",
+				strings.ReplaceAll(string(finding.Type), "_", " "), strings.ToLower(finding.RiskLevel.String()), finding.Confidence*100)
+			if value != "" {
+				message += ": " + value
+			}
+
+			results = append(results, sarifResult{
+				RuleID:    string(finding.Type),
+				RuleIndex: ruleIndex[string(finding.Type)],
+				Level:     sarifLevel(finding.RiskLevel),
+				Message:   sarifMessage{Text: message},
+				Locations: []sarifLocation{location},
+				Properties: sarifProperties{
+					Risk:       strings.ToLower(finding.RiskLevel.String()),
+					Confidence: finding.Confidence,
+				},
+			})
+		}
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: SARIFVersion,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "gart",
+				Version:        toolVersion,
+				InformationURI: "https://github.com/bnema/gart",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
+
+// sarifLevel maps a risk level to the SARIF result levels
+func sarifLevel(level RiskLevel) string {
+	switch {
+	case level >= RiskLevelHigh:
+		return "error"
+	case level == RiskLevelMedium:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// sarifURI returns the path relative to baseDir when it is below it, or a file URI
+func sarifURI(path, baseDir string) string {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	if baseDir != "" {
+		if rel, err := filepath.Rel(baseDir, absPath); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return "file://" + filepath.ToSlash(absPath)
+}
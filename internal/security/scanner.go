@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/bnema/gart/internal/system"
 )
 
 type RiskLevel int
@@ -34,6 +36,16 @@ func (r RiskLevel) String() string {
 	}
 }
 
+// ParseRiskLevel returns the risk level with the given name, e.g. "high", ignoring case
+func ParseRiskLevel(name string) (RiskLevel, error) {
+	for level := RiskLevelNone; level <= RiskLevelCritical; level++ {
+		if strings.EqualFold(name, level.String()) {
+			return level, nil
+		}
+	}
+	return RiskLevelNone, fmt.Errorf("invalid risk level '%s': must be one of: none, low, medium, high, critical", name)
+}
+
 type SecretType string
 
 const (
@@ -53,6 +65,20 @@ const (
 	SecretTypeSSN           SecretType = "ssn"
 	SecretTypeEmail         SecretType = "email"
 	SecretTypeIPAddress     SecretType = "ip_address"
+
+	SecretTypeSlackToken        SecretType = "slack_token"
+	SecretTypeStripeKey         SecretType = "stripe_key"
+	SecretTypeGitLabToken       SecretType = "gitlab_token"
+	SecretTypeNPMToken          SecretType = "npm_token"
+	SecretTypePyPIToken         SecretType = "pypi_token"
+	SecretTypeDockerHubToken    SecretType = "dockerhub_token"
+	SecretTypeGCPServiceAccount SecretType = "gcp_service_account"
+	SecretTypeAzureKey          SecretType = "azure_key"
+	SecretTypeTwilioKey         SecretType = "twilio_key"
+	SecretTypeSendGridKey       SecretType = "sendgrid_key"
+	SecretTypeHuggingFaceToken  SecretType = "huggingface_token"
+	SecretTypeDiscordToken      SecretType = "discord_token"
+	SecretTypeTelegramToken     SecretType = "telegram_token"
 )
 
 type Location struct {
@@ -97,6 +123,10 @@ type ScanResult struct {
 	Risk     RiskLevel
 	Passed   bool
 	Error    error
+	// Accepted are the findings accepted in the baseline, left out of Findings
+	Accepted []Finding
+	// Allowed counts the findings silenced by gart:allow comments
+	Allowed int
 }
 
 type ScanReport struct {
@@ -106,12 +136,17 @@ type ScanReport struct {
 	SkippedFiles  int
 	TotalFindings int
 	HighestRisk   RiskLevel
+	// AcceptedFindings counts the findings accepted in the baseline
+	AcceptedFindings int
+	// AllowedFindings counts the findings silenced by gart:allow comments
+	AllowedFindings int
 }
 
 type Scanner struct {
 	config          *SecurityConfig
 	patternMatcher  *PatternMatcher
 	contentDetector *Detector
+	baseline        *Baseline
 }
 
 func NewScanner(config *SecurityConfig) *Scanner {
@@ -122,7 +157,45 @@ func NewScanner(config *SecurityConfig) *Scanner {
 	}
 }
 
+// SetBaseline leaves the findings accepted in the baseline out of the scan results
+func (s *Scanner) SetBaseline(baseline *Baseline) {
+	s.baseline = baseline
+}
+
+// ScanFile scans the content of a file, findings accepted in the baseline are set apart
 func (s *Scanner) ScanFile(path string, content []byte) (*ScanResult, error) {
+	if s.baseline == nil {
+		return s.scanFile(path, content)
+	}
+
+	// The baseline only holds hashes, which look like secrets
+	if baselineFile(path) == baselineFile(s.baseline.Path()) {
+		return &ScanResult{FilePath: path, Findings: []Finding{}, Risk: RiskLevelNone, Passed: true}, nil
+	}
+
+	result, err := s.scanFile(path, content)
+	if err != nil || len(result.Findings) == 0 {
+		return result, err
+	}
+
+	findings := []Finding{}
+	result.Risk = RiskLevelNone
+	for _, finding := range result.Findings {
+		if s.baseline.Contains(finding) {
+			result.Accepted = append(result.Accepted, finding)
+			continue
+		}
+		findings = append(findings, finding)
+		if finding.RiskLevel > result.Risk {
+			result.Risk = finding.RiskLevel
+		}
+	}
+	result.Findings = findings
+	result.Passed = result.Risk < RiskLevelHigh || (result.Risk == RiskLevelHigh && !s.config.FailOnSecrets)
+	return result, nil
+}
+
+func (s *Scanner) scanFile(path string, content []byte) (*ScanResult, error) {
 	result := &ScanResult{
 		FilePath: path,
 		Findings: []Finding{},
@@ -135,6 +208,11 @@ func (s *Scanner) ScanFile(path string, content []byte) (*ScanResult, error) {
 		return result, nil
 	}
 
+	// Age encrypted files are safe to store whatever their name or content
+	if system.IsEncrypted(content) {
+		return result, nil
+	}
+
 	// Adjust sensitivity based on file type
 	ext := strings.ToLower(filepath.Ext(path))
 	originalSensitivity := s.config.Sensitivity
@@ -180,8 +258,9 @@ func (s *Scanner) ScanFile(path string, content []byte) (*ScanResult, error) {
 			return result, nil
 		}
 
-		findings := s.contentDetector.DetectSecrets(content, path)
+		findings, allowed := s.contentDetector.detectSecrets(content, path)
 		result.Findings = append(result.Findings, findings...)
+		result.Allowed = allowed
 
 		// Update risk level based on findings
 		for _, finding := range findings {
@@ -232,18 +311,9 @@ func (s *Scanner) ScanDirectory(path string, ignores []string) (*ScanReport, err
 		report.TotalFiles++
 
 		// Check if file should be ignored
-		for _, ignore := range ignores {
-			// Check against basename
-			if matched, _ := filepath.Match(ignore, filepath.Base(filePath)); matched {
-				report.SkippedFiles++
-				return nil
-			}
-			// Check against relative path from scan root
-			relPath, _ := filepath.Rel(path, filePath)
-			if matched, _ := filepath.Match(ignore, relPath); matched {
-				report.SkippedFiles++
-				return nil
-			}
+		if isIgnored(path, filePath, ignores) {
+			report.SkippedFiles++
+			return nil
 		}
 
 		// Read file content
@@ -262,6 +332,8 @@ func (s *Scanner) ScanDirectory(path string, ignores []string) (*ScanReport, err
 		report.ScannedFiles++
 		report.Results = append(report.Results, *result)
 		report.TotalFindings += len(result.Findings)
+		report.AcceptedFindings += len(result.Accepted)
+		report.AllowedFindings += result.Allowed
 
 		if result.Risk > report.HighestRisk {
 			report.HighestRisk = result.Risk
@@ -273,6 +345,21 @@ func (s *Scanner) ScanDirectory(path string, ignores []string) (*ScanReport, err
 	return report, err
 }
 
+// isIgnored reports whether an ignore pattern matches the base name of a file or its path
+// relative to the scan root
+func isIgnored(root, filePath string, ignores []string) bool {
+	relPath, _ := filepath.Rel(root, filePath)
+	for _, ignore := range ignores {
+		if matched, _ := filepath.Match(ignore, filepath.Base(filePath)); matched {
+			return true
+		}
+		if matched, _ := filepath.Match(ignore, relPath); matched {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *Scanner) SetSensitivity(level SensitivityLevel) {
 	s.config.Sensitivity = level
 	// Update detector sensitivity
@@ -304,3 +391,42 @@ func (r *ScanReport) Summary() string {
 		r.HighestRisk,
 	)
 }
+
+// FlaggedFiles returns the paths of the scanned files with findings
+func (r *ScanReport) FlaggedFiles() []string {
+	var files []string
+	for _, result := range r.Results {
+		if len(result.Findings) > 0 {
+			files = append(files, result.FilePath)
+		}
+	}
+	return files
+}
+
+// Merge adds the results of another report. Files already in the report are counted
+// once, so overlapping scans don't report the same findings twice.
+func (r *ScanReport) Merge(other *ScanReport) {
+	seen := make(map[string]bool, len(r.Results))
+	for _, result := range r.Results {
+		seen[result.FilePath] = true
+	}
+
+	r.TotalFiles += other.TotalFiles
+	r.SkippedFiles += other.SkippedFiles
+	for _, result := range other.Results {
+		if seen[result.FilePath] {
+			r.TotalFiles--
+			continue
+		}
+		seen[result.FilePath] = true
+
+		r.Results = append(r.Results, result)
+		r.ScannedFiles++
+		r.TotalFindings += len(result.Findings)
+		r.AcceptedFindings += len(result.Accepted)
+		r.AllowedFindings += result.Allowed
+		if result.Risk > r.HighestRisk {
+			r.HighestRisk = result.Risk
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package security
+
+import (
+	"encoding/base64"
+	"hash/crc32"
+	"strings"
+)
+
+const base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// tokenValidators check the tokens whose format has a checksum or a verifiable structure,
+// pattern matches failing them are not reported
+var tokenValidators = map[SecretType]func(string) bool{
+	SecretTypeNPMToken:     validCRC32Token,
+	SecretTypeDiscordToken: validDiscordToken,
+}
+
+// validCRC32Token checks a token made of a prefix, an underscore, 30 random characters
+// and the base62 CRC32 checksum of those characters on 6 characters
+func validCRC32Token(token string) bool {
+	index := strings.IndexByte(token, '_')
+	if index < 0 || len(token)-index-1 != 36 {
+		return false
+	}
+	body := token[index+1:]
+	return base62(crc32.ChecksumIEEE([]byte(body[:30])), 6) == body[30:]
+}
+
+// base62 encodes n, left padded with zeros to width characters
+func base62(n uint32, width int) string {
+	var encoded []byte
+	for ; n > 0; n /= 62 {
+		encoded = append([]byte{base62Alphabet[n%62]}, encoded...)
+	}
+	for len(encoded) < width {
+		encoded = append([]byte{'0'}, encoded...)
+	}
+	return string(encoded)
+}
+
+// validDiscordToken checks the first part of a bot token is the base64 encoded ID of the bot
+func validDiscordToken(token string) bool {
+	id, _, _ := strings.Cut(token, ".")
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(id, "="))
+	if err != nil || len(decoded) < 17 {
+		return false
+	}
+	for _, char := range decoded {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,216 @@
+package system
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Conflict markers written around the diverging lines of a file changed on both sides
+const (
+	ConflictMarkerLocal = "<<<<<<< local"
+	ConflictMarkerSep   = "======="
+	ConflictMarkerStore = ">>>>>>> store"
+)
+
+// Conflict is a file that changed both locally and in the store since the last sync.
+// Path is relative to the dotfile root, "." for a single file.
+type Conflict struct {
+	Path  string
+	Local string
+	Store string
+}
+
+// FindConflicts compares both sides of a dotfile with their state at the last sync
+// and returns the files that changed on both sides to different contents
+func FindConflicts(local, store string, last SyncState, ignores []string, manifest *Manifest) ([]Conflict, error) {
+	localFiles, err := manifest.treeState(local, ignores)
+	if err != nil {
+		return nil, err
+	}
+	storeFiles, err := manifest.treeState(store, ignores)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[string]bool)
+	for _, files := range []map[string]FileState{localFiles, storeFiles, last.Local, last.Store} {
+		for path := range files {
+			paths[path] = true
+		}
+	}
+
+	var conflicts []Conflict
+	for _, path := range sortedKeys(paths) {
+		if !stateChanged(last.Local, localFiles, path) || !stateChanged(last.Store, storeFiles, path) {
+			continue
+		}
+
+		localState, localOK := localFiles[path]
+		storeState, storeOK := storeFiles[path]
+		// Both sides deleted the file or made the same edit
+		if localOK == storeOK && localState.Hash == storeState.Hash {
+			continue
+		}
+
+		conflicts = append(conflicts, Conflict{
+			Path:  path,
+			Local: filepath.Join(local, filepath.FromSlash(path)),
+			Store: filepath.Join(store, filepath.FromSlash(path)),
+		})
+	}
+	return conflicts, nil
+}
+
+// ChangesSince returns the files of each side of a dotfile that were created, deleted or
+// modified since the last sync. Files changed on both sides to the same content are left out.
+func ChangesSince(local, store string, last SyncState, ignores []string, manifest *Manifest) ([]string, []string, error) {
+	localFiles, err := manifest.treeState(local, ignores)
+	if err != nil {
+		return nil, nil, err
+	}
+	storeFiles, err := manifest.treeState(store, ignores)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	paths := make(map[string]bool)
+	for _, files := range []map[string]FileState{localFiles, storeFiles, last.Local, last.Store} {
+		for path := range files {
+			paths[path] = true
+		}
+	}
+
+	var localChanges, storeChanges []string
+	for _, path := range sortedKeys(paths) {
+		localChanged := stateChanged(last.Local, localFiles, path)
+		storeChanged := stateChanged(last.Store, storeFiles, path)
+		if localChanged && storeChanged {
+			localState, localOK := localFiles[path]
+			storeState, storeOK := storeFiles[path]
+			if localOK == storeOK && localState.Hash == storeState.Hash {
+				continue
+			}
+		}
+		if localChanged {
+			localChanges = append(localChanges, path)
+		}
+		if storeChanged {
+			storeChanges = append(storeChanges, path)
+		}
+	}
+	return localChanges, storeChanges, nil
+}
+
+// DifferingFiles returns the files whose content differs between both sides of a dotfile,
+// or that exist on one side only
+func DifferingFiles(local, store string, ignores []string, manifest *Manifest) ([]string, error) {
+	localFiles, err := manifest.treeState(local, ignores)
+	if err != nil {
+		return nil, err
+	}
+	storeFiles, err := manifest.treeState(store, ignores)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[string]bool)
+	for path, state := range localFiles {
+		if storeState, ok := storeFiles[path]; !ok || storeState.Hash != state.Hash {
+			paths[path] = true
+		}
+	}
+	for path := range storeFiles {
+		if _, ok := localFiles[path]; !ok {
+			paths[path] = true
+		}
+	}
+	return sortedKeys(paths), nil
+}
+
+// stateChanged reports whether a file was created, deleted or modified since it was recorded
+func stateChanged(before, after map[string]FileState, path string) bool {
+	beforeState, beforeOK := before[path]
+	afterState, afterOK := after[path]
+	if beforeOK != afterOK {
+		return true
+	}
+	return beforeOK && beforeState.Hash != afterState.Hash
+}
+
+// ConflictMarkers merges two versions of a file, surrounding every block of lines
+// that differs with local and store conflict markers
+func ConflictMarkers(local, store []byte) ([]byte, error) {
+	if IsBinary(local) || IsBinary(store) {
+		return nil, fmt.Errorf("cannot write conflict markers into a binary file")
+	}
+
+	var buf bytes.Buffer
+	var ours, theirs []string
+	flush := func() {
+		if len(ours) == 0 && len(theirs) == 0 {
+			return
+		}
+		buf.WriteString(ConflictMarkerLocal + "\n")
+		for _, line := range ours {
+			buf.WriteString(line + "\n")
+		}
+		buf.WriteString(ConflictMarkerSep + "\n")
+		for _, line := range theirs {
+			buf.WriteString(line + "\n")
+		}
+		buf.WriteString(ConflictMarkerStore + "\n")
+		ours, theirs = nil, nil
+	}
+
+	for _, op := range diffLines(string(local), string(store)) {
+		switch op.kind {
+		case '-':
+			ours = append(ours, op.text)
+		case '+':
+			theirs = append(theirs, op.text)
+		default:
+			flush()
+			buf.WriteString(op.text + "\n")
+		}
+	}
+	flush()
+	return buf.Bytes(), nil
+}
+
+// HasConflictMarkers reports whether a file still contains unresolved conflict markers
+func HasConflictMarkers(content []byte) bool {
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.HasPrefix(line, ConflictMarkerLocal) || strings.HasPrefix(line, ConflictMarkerStore) {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteConflictFile replaces the local version of a conflicting file with the conflict
+// markers between the local and store versions. A file missing on one side is treated
+// as empty.
+func WriteConflictFile(path string, store []byte) error {
+	local, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	merged, err := ConflictMarkers(local, store)
+	if err != nil {
+		return err
+	}
+
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, merged, mode)
+}
@@ -0,0 +1,153 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindConflicts(t *testing.T) {
+	tempDir := t.TempDir()
+	local := filepath.Join(tempDir, "config", "nvim")
+	store := filepath.Join(tempDir, "store", "nvim")
+
+	for _, root := range []string{local, store} {
+		for name, content := range map[string]string{
+			"both.lua":  "-- base",
+			"local.lua": "-- base",
+			"store.lua": "-- base",
+			"same.lua":  "-- base",
+		} {
+			require.NoError(t, os.MkdirAll(root, 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(root, name), []byte(content), 0644))
+		}
+	}
+
+	manifest, err := LoadManifest(filepath.Join(tempDir, "store.manifest.toml"))
+	require.NoError(t, err)
+	require.NoError(t, manifest.RecordSync("nvim", local, store, nil))
+	last, _ := manifest.LastSync("nvim")
+
+	edits := map[string]string{
+		filepath.Join(local, "both.lua"):  "-- local edit",
+		filepath.Join(store, "both.lua"):  "-- store edit",
+		filepath.Join(local, "local.lua"): "-- local only",
+		filepath.Join(store, "store.lua"): "-- store only",
+		filepath.Join(local, "same.lua"):  "-- same edit",
+		filepath.Join(store, "same.lua"):  "-- same edit",
+		filepath.Join(local, "new.lua"):   "-- created locally",
+		filepath.Join(store, "new.lua"):   "-- created in store",
+	}
+	for path, content := range edits {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	conflicts, err := FindConflicts(local, store, last, nil, manifest)
+	require.NoError(t, err)
+
+	var paths []string
+	for _, conflict := range conflicts {
+		paths = append(paths, conflict.Path)
+	}
+	assert.Equal(t, []string{"both.lua", "new.lua"}, paths)
+	assert.Equal(t, filepath.Join(local, "both.lua"), conflicts[0].Local)
+	assert.Equal(t, filepath.Join(store, "both.lua"), conflicts[0].Store)
+}
+
+func TestChangesSince(t *testing.T) {
+	tempDir := t.TempDir()
+	local := filepath.Join(tempDir, "config", "nvim")
+	store := filepath.Join(tempDir, "store", "nvim")
+
+	for _, root := range []string{local, store} {
+		require.NoError(t, os.MkdirAll(root, 0755))
+		for _, name := range []string{"both.lua", "local.lua", "store.lua", "same.lua", "gone.lua"} {
+			require.NoError(t, os.WriteFile(filepath.Join(root, name), []byte("-- base"), 0644))
+		}
+	}
+
+	manifest, err := LoadManifest(filepath.Join(tempDir, "store.manifest.toml"))
+	require.NoError(t, err)
+	require.NoError(t, manifest.RecordSync("nvim", local, store, nil))
+	last, _ := manifest.LastSync("nvim")
+
+	localChanges, storeChanges, err := ChangesSince(local, store, last, nil, manifest)
+	require.NoError(t, err)
+	assert.Empty(t, localChanges)
+	assert.Empty(t, storeChanges)
+
+	for path, content := range map[string]string{
+		filepath.Join(local, "both.lua"):  "-- local edit",
+		filepath.Join(store, "both.lua"):  "-- store edit",
+		filepath.Join(local, "local.lua"): "-- local only",
+		filepath.Join(store, "store.lua"): "-- store only",
+		filepath.Join(local, "same.lua"):  "-- same edit",
+		filepath.Join(store, "same.lua"):  "-- same edit",
+	} {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	require.NoError(t, os.Remove(filepath.Join(local, "gone.lua")))
+
+	localChanges, storeChanges, err = ChangesSince(local, store, last, nil, manifest)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"both.lua", "gone.lua", "local.lua"}, localChanges)
+	assert.Equal(t, []string{"both.lua", "store.lua"}, storeChanges)
+}
+
+func TestDifferingFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	local := filepath.Join(tempDir, "config", "nvim")
+	store := filepath.Join(tempDir, "store", "nvim")
+	for path, content := range map[string]string{
+		filepath.Join(local, "same.lua"):    "-- same",
+		filepath.Join(store, "same.lua"):    "-- same",
+		filepath.Join(local, "changed.lua"): "-- local",
+		filepath.Join(store, "changed.lua"): "-- store",
+		filepath.Join(local, "new.lua"):     "-- local only",
+		filepath.Join(store, "old.lua"):     "-- store only",
+		filepath.Join(local, "debug.log"):   "ignored",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	manifest, err := LoadManifest(filepath.Join(tempDir, "store.manifest.toml"))
+	require.NoError(t, err)
+
+	files, err := DifferingFiles(local, store, []string{"*.log"}, manifest)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"changed.lua", "new.lua", "old.lua"}, files)
+}
+
+func TestConflictMarkers(t *testing.T) {
+	local := []byte("a\nlocal\nc\n")
+	store := []byte("a\nstore\nc\n")
+
+	merged, err := ConflictMarkers(local, store)
+	require.NoError(t, err)
+	assert.Equal(t, "a\n<<<<<<< local\nlocal\n=======\nstore\n>>>>>>> store\nc\n", string(merged))
+	assert.True(t, HasConflictMarkers(merged))
+	assert.False(t, HasConflictMarkers(local))
+
+	_, err = ConflictMarkers([]byte{0x00, 0x01}, store)
+	assert.Error(t, err)
+}
+
+func TestWriteConflictFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "init.lua")
+	require.NoError(t, os.WriteFile(path, []byte("local\n"), 0600))
+
+	require.NoError(t, WriteConflictFile(path, []byte("store\n")))
+	assert.NoFileExists(t, path+".orig", "No copy may be left next to the file, it would be synced")
+
+	merged, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "<<<<<<< local\nlocal\n=======\nstore\n>>>>>>> store\n", string(merged))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
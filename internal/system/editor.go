@@ -15,3 +15,12 @@ func GetEditor() string {
 	}
 	return "vi" // Fallback to vi if no editor is set
 }
+
+// OpenInEditor opens a file in the user's editor and waits for it to exit
+func OpenInEditor(path string) error {
+	cmd := exec.Command(GetEditor(), path)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
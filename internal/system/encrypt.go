@@ -0,0 +1,220 @@
+package system
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+// ageBinaryHeader is the first line of a binary age file
+const ageBinaryHeader = "age-encryption.org/v1"
+
+// maxDecrypted bounds the decryptions an Encrypter keeps, a long running watch would
+// otherwise keep every version of the encrypted files
+const maxDecrypted = 1024
+
+// Encrypter encrypts and decrypts dotfile content with age
+type Encrypter struct {
+	recipients []age.Recipient
+	identities []age.Identity
+
+	// decrypted caches plaintexts by ciphertext digest. Passphrase identities run scrypt on
+	// every decryption and a sync reads the same store files several times.
+	decrypted map[[sha256.Size]byte][]byte
+	mu        sync.Mutex
+}
+
+// NewX25519Encrypter returns an Encrypter using the age identity file at identityPath,
+// generating a new X25519 identity there if the file does not exist yet. Content is
+// encrypted to the identity and to the additional recipients (age public keys).
+func NewX25519Encrypter(identityPath string, recipients []string) (*Encrypter, error) {
+	data, err := os.ReadFile(identityPath)
+	if os.IsNotExist(err) {
+		data, err = generateIdentityFile(identityPath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read age identity: %w", err)
+	}
+
+	identities, err := age.ParseIdentities(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse age identity %s: %w", identityPath, err)
+	}
+
+	enc := &Encrypter{identities: identities}
+	for _, identity := range identities {
+		if x25519, ok := identity.(*age.X25519Identity); ok {
+			enc.recipients = append(enc.recipients, x25519.Recipient())
+		}
+	}
+	for _, recipient := range recipients {
+		parsed, err := age.ParseX25519Recipient(recipient)
+		if err != nil {
+			return nil, fmt.Errorf("invalid age recipient %q: %w", recipient, err)
+		}
+		enc.recipients = append(enc.recipients, parsed)
+	}
+
+	if len(enc.recipients) == 0 {
+		return nil, fmt.Errorf("no X25519 identity found in %s", identityPath)
+	}
+	return enc, nil
+}
+
+// NewPassphraseEncrypter returns an Encrypter deriving its key from a passphrase
+func NewPassphraseEncrypter(passphrase string) (*Encrypter, error) {
+	if passphrase == "" {
+		return nil, errors.New("empty passphrase")
+	}
+
+	recipient, err := age.NewScryptRecipient(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	identity, err := age.NewScryptIdentity(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Encrypter{
+		recipients: []age.Recipient{recipient},
+		identities: []age.Identity{identity},
+	}, nil
+}
+
+// generateIdentityFile writes a new X25519 identity to path and returns its content
+func generateIdentityFile(path string) ([]byte, error) {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		return nil, err
+	}
+
+	data := []byte(fmt.Sprintf("# created: %s\n# public key: %s\n%s\n",
+		time.Now().Format(time.RFC3339), identity.Recipient(), identity))
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Encrypt returns the ASCII-armored age encryption of content. The content is remembered
+// as the decryption of the result.
+func (e *Encrypter) Encrypt(content []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	armored := armor.NewWriter(&buf)
+
+	w, err := age.Encrypt(armored, e.recipients...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt: %w", err)
+	}
+	if _, err := w.Write(content); err != nil {
+		return nil, fmt.Errorf("failed to encrypt: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encrypt: %w", err)
+	}
+	if err := armored.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encrypt: %w", err)
+	}
+
+	e.remember(sha256.Sum256(buf.Bytes()), content)
+	return buf.Bytes(), nil
+}
+
+// Decrypt decrypts armored or binary age content. Content already decrypted by the
+// Encrypter is not decrypted again.
+func (e *Encrypter) Decrypt(content []byte) ([]byte, error) {
+	digest := sha256.Sum256(content)
+	e.mu.Lock()
+	plain, ok := e.decrypted[digest]
+	e.mu.Unlock()
+	if ok {
+		return bytes.Clone(plain), nil
+	}
+
+	plain, err := e.decrypt(content)
+	if err != nil {
+		return nil, err
+	}
+
+	e.remember(digest, plain)
+	return plain, nil
+}
+
+// remember caches the plaintext of the ciphertext with the given digest
+func (e *Encrypter) remember(digest [sha256.Size]byte, plain []byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.decrypted == nil || len(e.decrypted) >= maxDecrypted {
+		e.decrypted = make(map[[sha256.Size]byte][]byte)
+	}
+	e.decrypted[digest] = bytes.Clone(plain)
+}
+
+// decrypt runs the age decryption of content
+func (e *Encrypter) decrypt(content []byte) ([]byte, error) {
+	var src io.Reader = bytes.NewReader(content)
+	if strings.HasPrefix(strings.TrimSpace(string(content)), armor.Header) {
+		src = armor.NewReader(src)
+	}
+
+	r, err := age.Decrypt(src, e.identities...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+	plain, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+	return plain, nil
+}
+
+// IsEncrypted reports whether content is an armored or binary age file
+func IsEncrypted(content []byte) bool {
+	trimmed := bytes.TrimLeft(content, " \t\r\n")
+	return bytes.HasPrefix(trimmed, []byte(armor.Header)) || bytes.HasPrefix(content, []byte(ageBinaryHeader))
+}
+
+// EncryptTree copies the plain path src to dst, encrypting every regular file.
+// When a file under cipherRoot already decrypts to the same content, its
+// ciphertext is reused so unchanged files are not rewritten on every sync.
+func EncryptTree(src, cipherRoot, dst string, enc *Encrypter) error {
+	return transformTree(src, dst, false, func(rel string, content []byte) ([]byte, error) {
+		if existing, err := os.ReadFile(filepath.Join(cipherRoot, rel)); err == nil && IsEncrypted(existing) {
+			if plain, err := enc.Decrypt(existing); err == nil && bytes.Equal(plain, content) {
+				return existing, nil
+			}
+		}
+		return enc.Encrypt(content)
+	})
+}
+
+// DecryptTree copies src to dst, decrypting every age encrypted file.
+// Files that are not encrypted are copied as they are.
+func DecryptTree(src, dst string, enc *Encrypter) error {
+	return transformTree(src, dst, false, func(rel string, content []byte) ([]byte, error) {
+		if !IsEncrypted(content) {
+			return content, nil
+		}
+		plain, err := enc.Decrypt(content)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Join(src, rel), err)
+		}
+		return plain, nil
+	})
+}
@@ -0,0 +1,121 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncrypter_RoundTrip(t *testing.T) {
+	identityPath := filepath.Join(t.TempDir(), "keys", "age.key")
+
+	enc, err := NewX25519Encrypter(identityPath, nil)
+	require.NoError(t, err)
+	assert.FileExists(t, identityPath, "A missing identity must be generated")
+
+	info, err := os.Stat(identityPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	plain := []byte("machine example.com login user password hunter2\n")
+	ciphertext, err := enc.Encrypt(plain)
+	require.NoError(t, err)
+	assert.True(t, IsEncrypted(ciphertext))
+	assert.NotContains(t, string(ciphertext), "hunter2")
+
+	// A second encrypter loads the generated identity
+	reloaded, err := NewX25519Encrypter(identityPath, nil)
+	require.NoError(t, err)
+	decrypted, err := reloaded.Decrypt(ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, plain, decrypted)
+
+	other, err := NewX25519Encrypter(filepath.Join(t.TempDir(), "other.key"), nil)
+	require.NoError(t, err)
+	_, err = other.Decrypt(ciphertext)
+	assert.Error(t, err, "A foreign identity must not decrypt the content")
+}
+
+func TestNewX25519Encrypter_InvalidRecipient(t *testing.T) {
+	_, err := NewX25519Encrypter(filepath.Join(t.TempDir(), "age.key"), []string{"age1invalid"})
+	assert.Error(t, err)
+}
+
+func TestIsEncrypted(t *testing.T) {
+	assert.False(t, IsEncrypted([]byte("plain text")))
+	assert.True(t, IsEncrypted([]byte("-----BEGIN AGE ENCRYPTED FILE-----\nYWdl\n-----END AGE ENCRYPTED FILE-----\n")))
+	assert.True(t, IsEncrypted([]byte("age-encryption.org/v1\n-> X25519 abc\n")))
+}
+
+func TestEncryptTree(t *testing.T) {
+	tempDir := t.TempDir()
+	enc, err := NewX25519Encrypter(filepath.Join(tempDir, "age.key"), nil)
+	require.NoError(t, err)
+
+	src := filepath.Join(tempDir, "ssh")
+	require.NoError(t, os.MkdirAll(src, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "config"), []byte("Host *\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "id_ed25519"), []byte{0x00, 0x01, 0x02}, 0600))
+
+	store := filepath.Join(tempDir, "store", "ssh")
+	require.NoError(t, EncryptTree(src, store, store, enc))
+
+	for _, name := range []string{"config", "id_ed25519"} {
+		content, err := os.ReadFile(filepath.Join(store, name))
+		require.NoError(t, err)
+		assert.True(t, IsEncrypted(content), "%s must be encrypted, binary files included", name)
+	}
+
+	// Unchanged files keep their ciphertext, changed files are encrypted again
+	before, err := os.ReadFile(filepath.Join(store, "id_ed25519"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(src, "config"), []byte("Host github.com\n"), 0644))
+
+	staged := filepath.Join(tempDir, "staged", "ssh")
+	require.NoError(t, EncryptTree(src, store, staged, enc))
+	after, err := os.ReadFile(filepath.Join(staged, "id_ed25519"))
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+
+	decrypted := filepath.Join(tempDir, "decrypted", "ssh")
+	require.NoError(t, DecryptTree(staged, decrypted, enc))
+	content, err := os.ReadFile(filepath.Join(decrypted, "config"))
+	require.NoError(t, err)
+	assert.Equal(t, "Host github.com\n", string(content))
+}
+
+func TestEncrypter_DecryptsOnce(t *testing.T) {
+	tempDir := t.TempDir()
+	enc, err := NewX25519Encrypter(filepath.Join(tempDir, "age.key"), nil)
+	require.NoError(t, err)
+
+	src := filepath.Join(tempDir, "netrc")
+	require.NoError(t, os.WriteFile(src, []byte("machine example.com\n"), 0600))
+	store := filepath.Join(tempDir, "store", "netrc")
+	require.NoError(t, EncryptTree(src, store, store, enc))
+	ciphertext, err := os.ReadFile(store)
+	require.NoError(t, err)
+
+	// Encrypted content is remembered, decrypting it does not add an entry
+	assert.Len(t, enc.decrypted, 1)
+	require.NoError(t, DecryptTree(store, filepath.Join(tempDir, "decrypted", "netrc"), enc))
+	assert.Len(t, enc.decrypted, 1)
+
+	// Without identities only the cached decryptions can succeed
+	enc.identities = nil
+	staged := filepath.Join(tempDir, "staged", "netrc")
+	require.NoError(t, EncryptTree(src, store, staged, enc))
+	reused, err := os.ReadFile(staged)
+	require.NoError(t, err)
+	assert.Equal(t, ciphertext, reused, "the store ciphertext must be reused without decrypting it again")
+
+	plain, err := enc.Decrypt(ciphertext)
+	require.NoError(t, err)
+	plain[0] = 'M'
+	plain, err = enc.Decrypt(ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, "machine example.com\n", string(plain), "callers must not alter the cache")
+}
@@ -0,0 +1,103 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	gitignoreBegin = "# BEGIN gart symlinked dotfiles, generated from their ignores"
+	gitignoreEnd   = "# END gart symlinked dotfiles"
+)
+
+// GitignorePatterns translates the ignores of a dotfile stored at root, relative to the
+// repository, to .gitignore patterns matching the same files below that entry
+func GitignorePatterns(root string, ignores []string) []string {
+	anchor := "/" + escapeGitignore(filepath.ToSlash(root)) + "/**/"
+
+	var patterns []string
+	for _, ignore := range ignores {
+		ignore = strings.TrimPrefix(filepath.ToSlash(ignore), "/")
+
+		switch {
+		case strings.HasSuffix(ignore, "/"):
+			// Directory patterns match any path component
+			patterns = append(patterns, anchor+strings.TrimPrefix(ignore, "**/"))
+		case strings.HasSuffix(ignore, "/**"):
+			patterns = append(patterns, anchor+strings.TrimSuffix(ignore, "**"))
+		case strings.Contains(ignore, "{") && strings.Contains(ignore, "}"):
+			startBrace := strings.Index(ignore, "{")
+			endBrace := strings.Index(ignore, "}")
+			if startBrace <= 0 || endBrace < startBrace {
+				continue
+			}
+			for _, ext := range strings.Split(ignore[startBrace+1:endBrace], ",") {
+				patterns = append(patterns, anchor+ignore[:startBrace]+strings.TrimSpace(ext))
+			}
+		case ignore != "":
+			patterns = append(patterns, anchor+ignore)
+		}
+	}
+	return patterns
+}
+
+// escapeGitignore escapes the characters of a literal path that .gitignore reads as a pattern
+func escapeGitignore(path string) string {
+	var escaped strings.Builder
+	for _, char := range path {
+		if strings.ContainsRune(`\*?[!# `, char) {
+			escaped.WriteRune('\\')
+		}
+		escaped.WriteRune(char)
+	}
+	return escaped.String()
+}
+
+// WriteGitignoreSection replaces the section of the .gitignore file at path managed by gart
+// with patterns, keeping the lines written by the user. The section is removed when there are
+// no patterns and the file is only rewritten when its content changes.
+func WriteGitignoreSection(path string, patterns []string) error {
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error reading %s: %w", path, err)
+	}
+
+	var lines []string
+	inSection := false
+	for _, line := range strings.Split(strings.TrimRight(string(content), "\n"), "\n") {
+		switch {
+		case line == gitignoreBegin:
+			inSection = true
+		case line == gitignoreEnd:
+			inSection = false
+		case !inSection:
+			lines = append(lines, line)
+		}
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	if len(patterns) > 0 {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, gitignoreBegin)
+		lines = append(lines, patterns...)
+		lines = append(lines, gitignoreEnd)
+	}
+
+	updated := ""
+	if len(lines) > 0 {
+		updated = strings.Join(lines, "\n") + "\n"
+	}
+	if updated == string(content) {
+		return nil
+	}
+	if updated == "" {
+		return os.Remove(path)
+	}
+	return os.WriteFile(path, []byte(updated), 0644)
+}
@@ -0,0 +1,46 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitignorePatterns(t *testing.T) {
+	patterns := GitignorePatterns("nvim", []string{"lazy/", "**/.cache/", "node_modules/**", "*.{swp,tmp}", "*.log"})
+	assert.Equal(t, []string{
+		"/nvim/**/lazy/",
+		"/nvim/**/.cache/",
+		"/nvim/**/node_modules/",
+		"/nvim/**/*.swp",
+		"/nvim/**/*.tmp",
+		"/nvim/**/*.log",
+	}, patterns)
+
+	// Store entries are literal paths
+	assert.Equal(t, []string{`/\#notes\ app/**/*.log`}, GitignorePatterns("#notes app", []string{"*.log"}))
+}
+
+func TestWriteGitignoreSection(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".gitignore")
+	require.NoError(t, os.WriteFile(path, []byte("*.bak\n"), 0644))
+
+	require.NoError(t, WriteGitignoreSection(path, []string{"/nvim/**/lazy/"}))
+	require.NoError(t, WriteGitignoreSection(path, []string{"/nvim/**/lazy/", "/kitty/**/*.log"}))
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "*.bak\n\n"+gitignoreBegin+"\n/nvim/**/lazy/\n/kitty/**/*.log\n"+gitignoreEnd+"\n", string(content))
+
+	// User lines survive the removal of the section
+	require.NoError(t, WriteGitignoreSection(path, nil))
+	content, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "*.bak\n", string(content))
+
+	require.NoError(t, os.Remove(path))
+	require.NoError(t, WriteGitignoreSection(path, nil))
+	assert.NoFileExists(t, path)
+}
@@ -0,0 +1,8 @@
+//go:build windows
+
+package system
+
+import "os/exec"
+
+// hookCommand leaves the hook in the process group of gart on Windows
+func hookCommand(cmd *exec.Cmd) {}
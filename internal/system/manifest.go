@@ -0,0 +1,303 @@
+package system
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pelletier/go-toml"
+)
+
+// racyWindow is how recent a modification time must be for the cached hash of a file
+// not to be trusted: the file may still change within the same timestamp tick
+const racyWindow = 2 * time.Second
+
+// FileState is the stat information and content hash of a file
+type FileState struct {
+	Size    int64     `toml:"size"`
+	ModTime time.Time `toml:"mtime"`
+	Mode    uint32    `toml:"mode"`
+	Hash    string    `toml:"sha256"`
+}
+
+// matches reports whether the state was recorded for a file with the given stat information
+func (s FileState) matches(info os.FileInfo) bool {
+	return s.Hash != "" &&
+		s.Size == info.Size() &&
+		s.ModTime.Equal(info.ModTime()) &&
+		s.Mode == uint32(info.Mode())
+}
+
+// SyncState records both sides of a dotfile as they were after its last sync.
+// Files are keyed by their path relative to the dotfile root, "." for a single file.
+type SyncState struct {
+	SyncedAt time.Time            `toml:"synced_at"`
+	Local    map[string]FileState `toml:"local"`
+	Store    map[string]FileState `toml:"store"`
+}
+
+// Manifest caches the content hashes of the files gart compares, keyed by absolute
+// path, and the state of every dotfile at its last sync
+type Manifest struct {
+	Files    map[string]FileState `toml:"files"`
+	Dotfiles map[string]SyncState `toml:"dotfiles"`
+
+	path string
+	mu   sync.Mutex
+}
+
+// manifestFile is the content of the manifest file. Manifest is never encoded directly:
+// the encoder would copy its mutex while other goroutines hold it.
+type manifestFile struct {
+	Files    map[string]FileState `toml:"files"`
+	Dotfiles map[string]SyncState `toml:"dotfiles"`
+}
+
+// LoadManifest reads the manifest at path. A missing file yields an empty manifest.
+func LoadManifest(path string) (*Manifest, error) {
+	var file manifestFile
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("error reading manifest: %w", err)
+	}
+	if err == nil {
+		if err := toml.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("error parsing manifest %s: %w", path, err)
+		}
+	}
+
+	m := &Manifest{Files: file.Files, Dotfiles: file.Dotfiles, path: path}
+
+	if m.Files == nil {
+		m.Files = make(map[string]FileState)
+	}
+	if m.Dotfiles == nil {
+		m.Dotfiles = make(map[string]SyncState)
+	}
+	return m, nil
+}
+
+// Save writes the manifest back to the file it was loaded from
+func (m *Manifest) Save() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Forget files that no longer exist so the cache does not grow forever
+	for path := range m.Files {
+		if _, err := os.Lstat(path); os.IsNotExist(err) {
+			delete(m.Files, path)
+		}
+	}
+
+	data, err := toml.Marshal(manifestFile{Files: m.Files, Dotfiles: m.Dotfiles})
+	if err != nil {
+		return fmt.Errorf("error encoding manifest: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(m.path), 0755); err != nil {
+		return fmt.Errorf("error creating manifest directory: %w", err)
+	}
+
+	// Write to a temporary file first so an interrupted save never truncates the manifest
+	tmp := m.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("error writing manifest: %w", err)
+	}
+	return os.Rename(tmp, m.path)
+}
+
+// Hash returns the SHA-256 of a file, reusing the cached hash when its size,
+// modification time and mode did not change. A nil manifest always hashes the file.
+func (m *Manifest) Hash(path string) (string, error) {
+	state, err := m.State(path)
+	if err != nil {
+		return "", err
+	}
+	return state.Hash, nil
+}
+
+// State returns the current state of a file, hashing it only when the cached entry is stale
+func (m *Manifest) State(path string) (FileState, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return FileState{}, err
+	}
+
+	key := path
+	if m != nil {
+		if abs, err := filepath.Abs(path); err == nil {
+			key = abs
+		}
+		m.mu.Lock()
+		cached, ok := m.Files[key]
+		m.mu.Unlock()
+		if ok && cached.matches(info) {
+			return cached, nil
+		}
+	}
+
+	hash, err := HashFile(path)
+	if err != nil {
+		return FileState{}, err
+	}
+	state := FileState{
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+		Mode:    uint32(info.Mode()),
+		Hash:    hash,
+	}
+
+	if m != nil && time.Since(info.ModTime()) > racyWindow {
+		m.mu.Lock()
+		m.Files[key] = state
+		m.mu.Unlock()
+	}
+	return state, nil
+}
+
+// RecordSync stores the state of both sides of a dotfile after a sync
+func (m *Manifest) RecordSync(name, local, store string, ignores []string) error {
+	return m.RecordSyncExcluding(name, local, store, ignores, nil)
+}
+
+// RecordSyncExcluding stores the state of both sides of a dotfile after a sync that left the
+// exclude paths, on either side, untouched. Their previous state is kept so that their
+// changes are still pending on the next sync.
+func (m *Manifest) RecordSyncExcluding(name, local, store string, ignores, exclude []string) error {
+	localFiles, err := m.treeState(local, ignores)
+	if err != nil {
+		return fmt.Errorf("error recording %s: %w", local, err)
+	}
+	storeFiles, err := m.treeState(store, ignores)
+	if err != nil {
+		return fmt.Errorf("error recording %s: %w", store, err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if previous, ok := m.Dotfiles[name]; ok {
+		for _, path := range exclude {
+			rel, ok := relativeTo(local, path)
+			if !ok {
+				if rel, ok = relativeTo(store, path); !ok {
+					continue
+				}
+			}
+			keepState(localFiles, previous.Local, rel)
+			keepState(storeFiles, previous.Store, rel)
+		}
+	}
+	m.Dotfiles[name] = SyncState{
+		SyncedAt: time.Now(),
+		Local:    localFiles,
+		Store:    storeFiles,
+	}
+	return nil
+}
+
+// relativeTo returns the slash separated path of path below root, "." for root itself
+func relativeTo(root, path string) (string, bool) {
+	root, path = filepath.Clean(root), filepath.Clean(path)
+	if path == root {
+		return ".", true
+	}
+	if !strings.HasPrefix(path, root+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(path[len(root)+1:]), true
+}
+
+// keepState replaces the states of the files at or below rel with their previous states
+func keepState(files, previous map[string]FileState, rel string) {
+	below := func(path string) bool {
+		return rel == "." || path == rel || strings.HasPrefix(path, rel+"/")
+	}
+	for path := range files {
+		if below(path) {
+			delete(files, path)
+		}
+	}
+	for path, state := range previous {
+		if below(path) {
+			files[path] = state
+		}
+	}
+}
+
+// LastSync returns the recorded state of a dotfile at its last sync
+func (m *Manifest) LastSync(name string) (SyncState, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state, ok := m.Dotfiles[name]
+	return state, ok
+}
+
+// Forget drops the recorded sync state of a dotfile
+func (m *Manifest) Forget(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.Dotfiles, name)
+}
+
+// treeState returns the state of every non-ignored regular file under root
+func (m *Manifest) treeState(root string, ignores []string) (map[string]FileState, error) {
+	files := make(map[string]FileState)
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == root {
+				return nil
+			}
+			return err
+		}
+		if path != root && shouldIgnore(path, ignores) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" || info.Name() == ".github" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		state, err := m.State(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = state
+		return nil
+	})
+	return files, err
+}
+
+// HashFile returns the hex encoded SHA-256 of a file's content
+func HashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,167 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManifestHashCache(t *testing.T) {
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "init.lua")
+	require.NoError(t, os.WriteFile(path, []byte("-- config"), 0644))
+
+	// Old enough to be outside the racy window
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(path, old, old))
+
+	manifest, err := LoadManifest(filepath.Join(tempDir, "store.manifest.toml"))
+	require.NoError(t, err)
+
+	hash, err := manifest.Hash(path)
+	require.NoError(t, err)
+	expected, err := HashFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, expected, hash)
+
+	// A stale cache entry with matching stat data is trusted without reading the file
+	abs, err := filepath.Abs(path)
+	require.NoError(t, err)
+	cached := manifest.Files[abs]
+	cached.Hash = "cached"
+	manifest.Files[abs] = cached
+	hash, err = manifest.Hash(path)
+	require.NoError(t, err)
+	assert.Equal(t, "cached", hash)
+
+	// Changing the file invalidates the cache entry
+	require.NoError(t, os.WriteFile(path, []byte("-- changed"), 0644))
+	hash, err = manifest.Hash(path)
+	require.NoError(t, err)
+	assert.NotEqual(t, "cached", hash)
+}
+
+func TestManifestRecentFilesNotCached(t *testing.T) {
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "init.lua")
+	require.NoError(t, os.WriteFile(path, []byte("-- config"), 0644))
+
+	manifest, err := LoadManifest(filepath.Join(tempDir, "store.manifest.toml"))
+	require.NoError(t, err)
+
+	_, err = manifest.Hash(path)
+	require.NoError(t, err)
+	assert.Empty(t, manifest.Files)
+}
+
+func TestManifestRecordSyncRoundTrip(t *testing.T) {
+	tempDir := t.TempDir()
+	local := filepath.Join(tempDir, "config", "nvim")
+	store := filepath.Join(tempDir, "store", "nvim")
+
+	files := map[string]string{
+		filepath.Join(local, "init.lua"):           "-- init",
+		filepath.Join(local, "lua", "plugins.lua"): "-- plugins",
+		filepath.Join(local, "debug.log"):          "ignored",
+		filepath.Join(store, "init.lua"):           "-- init",
+		filepath.Join(store, "lua", "plugins.lua"): "-- plugins",
+	}
+	for path, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	manifestPath := filepath.Join(tempDir, "store.manifest.toml")
+	manifest, err := LoadManifest(manifestPath)
+	require.NoError(t, err)
+	require.NoError(t, manifest.RecordSync("nvim", local, store, []string{"*.log"}))
+	require.NoError(t, manifest.Save())
+
+	loaded, err := LoadManifest(manifestPath)
+	require.NoError(t, err)
+	state, ok := loaded.LastSync("nvim")
+	require.True(t, ok)
+
+	assert.Len(t, state.Local, 2)
+	assert.Len(t, state.Store, 2)
+	assert.NotContains(t, state.Local, "debug.log")
+	assert.Equal(t, state.Local["lua/plugins.lua"].Hash, state.Store["lua/plugins.lua"].Hash)
+	assert.Equal(t, int64(len("-- init")), state.Local["init.lua"].Size)
+
+	loaded.Forget("nvim")
+	_, ok = loaded.LastSync("nvim")
+	assert.False(t, ok)
+}
+
+func TestManifestRecordSyncExcluding(t *testing.T) {
+	tempDir := t.TempDir()
+	local := filepath.Join(tempDir, "local")
+	store := filepath.Join(tempDir, "store")
+	require.NoError(t, os.MkdirAll(filepath.Join(local, "keys"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(local, "init.lua"), []byte("-- v1"), 0644))
+
+	manifest, err := LoadManifest(filepath.Join(tempDir, "manifest.toml"))
+	require.NoError(t, err)
+	require.NoError(t, manifest.RecordSync("nvim", local, store, nil))
+
+	require.NoError(t, os.WriteFile(filepath.Join(local, "init.lua"), []byte("-- v2"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(local, "keys", "token.lua"), []byte("-- secret"), 0644))
+	require.NoError(t, manifest.RecordSyncExcluding("nvim", local, store, nil, []string{filepath.Join(local, "keys")}))
+
+	state, ok := manifest.LastSync("nvim")
+	require.True(t, ok)
+	assert.Equal(t, int64(len("-- v2")), state.Local["init.lua"].Size)
+	assert.NotContains(t, state.Local, "keys/token.lua", "an excluded new file must not be recorded as synced")
+}
+
+func TestManifestConcurrentSave(t *testing.T) {
+	tempDir := t.TempDir()
+	local := filepath.Join(tempDir, "init.lua")
+	require.NoError(t, os.WriteFile(local, []byte("-- init"), 0644))
+
+	manifest, err := LoadManifest(filepath.Join(tempDir, "store.manifest.toml"))
+	require.NoError(t, err)
+
+	// Saving while other goroutines use the manifest must not race, run with -race
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				assert.NoError(t, manifest.RecordSync("nvim", local, local, nil))
+				manifest.LastSync("nvim")
+				assert.NoError(t, manifest.Save())
+			}
+		}()
+	}
+	wg.Wait()
+
+	loaded, err := LoadManifest(manifest.path)
+	require.NoError(t, err)
+	_, ok := loaded.LastSync("nvim")
+	assert.True(t, ok)
+}
+
+func TestPlanChangesBinaryFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	origin := filepath.Join(tempDir, "origin.bin")
+	dest := filepath.Join(tempDir, "dest.bin")
+
+	// Same size, invalid UTF-8, differing in a single byte
+	require.NoError(t, os.WriteFile(origin, []byte{0xff, 0x00, 0xfe, 0x01}, 0644))
+	require.NoError(t, os.WriteFile(dest, []byte{0xff, 0x00, 0xfe, 0x02}, 0644))
+
+	manifest, err := LoadManifest(filepath.Join(tempDir, "store.manifest.toml"))
+	require.NoError(t, err)
+
+	plan, err := PlanChangesWithManifest(origin, dest, nil, manifest)
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 1)
+	assert.Equal(t, ChangeModify, plan.Changes[0].Type)
+}
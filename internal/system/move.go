@@ -0,0 +1,55 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// MovePath moves a file or directory from src to dst, falling back to
+// copy and remove when src and dst are on different filesystems
+func MovePath(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("error creating destination directory: %w", err)
+	}
+
+	err := os.Rename(src, dst)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, syscall.EXDEV) {
+		return fmt.Errorf("error moving %s to %s: %w", src, dst, err)
+	}
+
+	if err := CopyPath(src, dst, nil); err != nil {
+		return fmt.Errorf("error copying %s to %s: %w", src, dst, err)
+	}
+	return RemoveDirectory(src)
+}
+
+// IsSymlinkTo reports whether path is a symlink pointing to target
+func IsSymlinkTo(path, target string) (bool, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if info.Mode()&os.ModeSymlink == 0 {
+		return false, nil
+	}
+
+	dest, err := os.Readlink(path)
+	if err != nil {
+		return false, err
+	}
+	if !filepath.IsAbs(dest) {
+		dest = filepath.Join(filepath.Dir(path), dest)
+	}
+
+	return filepath.Clean(dest) == filepath.Clean(target), nil
+}
@@ -0,0 +1,437 @@
+package system
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ChangeType describes the kind of file operation a sync would perform
+type ChangeType string
+
+const (
+	ChangeCreate     ChangeType = "create"
+	ChangeModify     ChangeType = "modify"
+	ChangeDelete     ChangeType = "delete"
+	ChangeTypeChange ChangeType = "type-change"
+)
+
+// Change is a single planned file operation.
+// Source is the path the content comes from, Target the path that gets written or removed.
+type Change struct {
+	Type   ChangeType
+	Source string
+	Target string
+	Size   int64
+	IsDir  bool
+}
+
+// Plan is the list of file operations needed to make a destination match its origin
+type Plan struct {
+	Changes []Change
+}
+
+// IsEmpty reports whether the plan contains no changes
+func (p *Plan) IsEmpty() bool {
+	return len(p.Changes) == 0
+}
+
+// TotalSize returns the number of bytes touched by the plan
+func (p *Plan) TotalSize() int64 {
+	var total int64
+	for _, change := range p.Changes {
+		total += change.Size
+	}
+	return total
+}
+
+// Count returns the number of changes of the given type
+func (p *Plan) Count(changeType ChangeType) int {
+	count := 0
+	for _, change := range p.Changes {
+		if change.Type == changeType {
+			count++
+		}
+	}
+	return count
+}
+
+// Without returns a copy of the plan without the changes of the given type
+func (p *Plan) Without(changeType ChangeType) *Plan {
+	filtered := &Plan{}
+	for _, change := range p.Changes {
+		if change.Type != changeType {
+			filtered.Changes = append(filtered.Changes, change)
+		}
+	}
+	return filtered
+}
+
+// Excluding returns a copy of the plan without the changes reading or writing one of the
+// given paths. A copied directory containing one of them is left out as a whole.
+func (p *Plan) Excluding(paths []string) *Plan {
+	filtered := &Plan{}
+	for _, change := range p.Changes {
+		if !touchesAny(change, paths) {
+			filtered.Changes = append(filtered.Changes, change)
+		}
+	}
+	return filtered
+}
+
+func touchesAny(change Change, paths []string) bool {
+	for _, path := range paths {
+		path = filepath.Clean(path)
+		for _, side := range []string{change.Source, change.Target} {
+			if side == "" {
+				continue
+			}
+			if path == side || (change.IsDir && strings.HasPrefix(path, side+string(filepath.Separator))) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// TargetChange is a file touched by a plan, relative to the destination of the plan
+type TargetChange struct {
+	Type ChangeType
+	Path string
+}
+
+// Targets returns the files touched by the plan relative to root, the destination it was
+// planned for. Copied directories are expanded to their files, and a plan for a single
+// file returns the file name. Ignored files of copied directories are left out.
+func (p *Plan) Targets(root string, ignores []string) []string {
+	changes := p.TargetChanges(root, ignores)
+	targets := make([]string, 0, len(changes))
+	for _, change := range changes {
+		targets = append(targets, change.Path)
+	}
+	return targets
+}
+
+// TargetChanges is like Targets but also returns the type of change of each file
+func (p *Plan) TargetChanges(root string, ignores []string) []TargetChange {
+	var targets []TargetChange
+	addTarget := func(changeType ChangeType, target string) {
+		rel, err := filepath.Rel(root, target)
+		if err != nil || rel == "." {
+			rel = filepath.Base(target)
+		}
+		targets = append(targets, TargetChange{Type: changeType, Path: filepath.ToSlash(rel)})
+	}
+
+	for _, change := range p.Changes {
+		if !change.IsDir || change.Type == ChangeDelete {
+			addTarget(change.Type, change.Target)
+			continue
+		}
+
+		err := filepath.WalkDir(change.Source, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if shouldIgnore(path, ignores) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() {
+				return nil
+			}
+			rel, err := filepath.Rel(change.Source, path)
+			if err != nil {
+				return err
+			}
+			addTarget(change.Type, filepath.Join(change.Target, rel))
+			return nil
+		})
+		if err != nil {
+			addTarget(change.Type, change.Target)
+		}
+	}
+	return targets
+}
+
+// PlanChanges compares origin with dest and returns the operations needed to make
+// dest match origin, without touching either side
+func PlanChanges(origin, dest string, ignores []string) (*Plan, error) {
+	return PlanChangesWithManifest(origin, dest, ignores, nil)
+}
+
+// PlanChangesWithManifest is like PlanChanges but reuses the content hashes cached in
+// the manifest for files whose size, modification time and mode did not change
+func PlanChangesWithManifest(origin, dest string, ignores []string, manifest *Manifest) (*Plan, error) {
+	plan := &Plan{}
+	if err := planRecursive(origin, dest, ignores, manifest, plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// PlanSync returns the plan for a sync between a local path and its store entry.
+// If reverseSyncMode is true, the store is considered the origin.
+func PlanSync(local, store string, ignores []string, reverseSyncMode bool) (*Plan, error) {
+	return PlanSyncWithManifest(local, store, ignores, reverseSyncMode, nil)
+}
+
+// PlanSyncWithManifest is like PlanSync but compares files through the manifest hash cache
+func PlanSyncWithManifest(local, store string, ignores []string, reverseSyncMode bool, manifest *Manifest) (*Plan, error) {
+	if reverseSyncMode {
+		return PlanChangesWithManifest(store, local, ignores, manifest)
+	}
+	return PlanChangesWithManifest(local, store, ignores, manifest)
+}
+
+// PlanFiles returns the operations copying the given files, relative to both roots, from
+// origin to dest. Files missing from origin are deleted from dest.
+func PlanFiles(origin, dest string, paths []string) (*Plan, error) {
+	plan := &Plan{}
+	for _, path := range paths {
+		source := filepath.Join(origin, filepath.FromSlash(path))
+		target := filepath.Join(dest, filepath.FromSlash(path))
+
+		sourceInfo, err := os.Stat(source)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		targetInfo, err := os.Stat(target)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		switch {
+		case sourceInfo == nil && targetInfo == nil:
+			continue
+		case sourceInfo == nil:
+			err = plan.add(ChangeDelete, "", target, targetInfo)
+		case targetInfo == nil:
+			err = plan.add(ChangeCreate, source, target, sourceInfo)
+		case sourceInfo.IsDir() != targetInfo.IsDir():
+			err = plan.add(ChangeTypeChange, source, target, sourceInfo)
+		default:
+			var differ bool
+			if differ, err = filesDiffer(source, sourceInfo, target, targetInfo, nil); err == nil && differ {
+				err = plan.add(ChangeModify, source, target, sourceInfo)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return plan, nil
+}
+
+// PlanRemoval returns the plan for deleting a path entirely
+func PlanRemoval(path string) (*Plan, error) {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return &Plan{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	size, err := pathSize(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Plan{Changes: []Change{{
+		Type:   ChangeDelete,
+		Target: path,
+		Size:   size,
+		IsDir:  info.IsDir(),
+	}}}, nil
+}
+
+// ApplyPlan performs the operations of a plan
+func ApplyPlan(plan *Plan, ignores []string) error {
+	for _, change := range plan.Changes {
+		var err error
+		switch change.Type {
+		case ChangeCreate, ChangeModify:
+			if err = os.MkdirAll(filepath.Dir(change.Target), 0755); err == nil {
+				_, err = copyItem(change.Source, change.Target, change.IsDir, ignores)
+			}
+		case ChangeTypeChange:
+			_, err = replaceItem(change.Source, change.Target, change.IsDir, ignores)
+		case ChangeDelete:
+			err = RemoveDirectory(change.Target)
+		default:
+			err = fmt.Errorf("unknown change type %q", change.Type)
+		}
+		if err != nil {
+			return fmt.Errorf("error applying %s of %s: %w", change.Type, change.Target, err)
+		}
+	}
+	return nil
+}
+
+func planRecursive(origin, dest string, ignores []string, manifest *Manifest, plan *Plan) error {
+	// Check if the path should be ignored before doing anything else
+	if shouldIgnore(origin, ignores) {
+		return nil
+	}
+
+	originInfo, err := os.Stat(origin)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	// Skip .git and .github directories
+	if originInfo.IsDir() && (originInfo.Name() == ".git" || originInfo.Name() == ".github") {
+		return nil
+	}
+
+	destInfo, err := os.Stat(dest)
+	if os.IsNotExist(err) {
+		return plan.add(ChangeCreate, origin, dest, originInfo)
+	} else if err != nil {
+		return err
+	}
+
+	switch {
+	case originInfo.IsDir() && destInfo.IsDir():
+		return planDirectories(origin, dest, ignores, manifest, plan)
+	case !originInfo.IsDir() && !destInfo.IsDir():
+		differ, err := filesDiffer(origin, originInfo, dest, destInfo, manifest)
+		if err != nil {
+			return err
+		}
+		if differ {
+			return plan.add(ChangeModify, origin, dest, originInfo)
+		}
+		return nil
+	default:
+		// One is a file, the other is a directory
+		return plan.add(ChangeTypeChange, origin, dest, originInfo)
+	}
+}
+
+// planDirectories compares the contents of two directories.
+func planDirectories(origin, dest string, ignores []string, manifest *Manifest, plan *Plan) error {
+	originNames, err := listEntries(origin, ignores)
+	if err != nil {
+		return err
+	}
+	destNames, err := listEntries(dest, ignores)
+	if err != nil {
+		return err
+	}
+
+	// New or modified entries in origin
+	for _, name := range sortedKeys(originNames) {
+		if err := planRecursive(filepath.Join(origin, name), filepath.Join(dest, name), ignores, manifest, plan); err != nil {
+			return err
+		}
+	}
+
+	// Entries that exist in dest but not in origin were deleted
+	for _, name := range sortedKeys(destNames) {
+		if originNames[name] {
+			continue
+		}
+		destPath := filepath.Join(dest, name)
+		info, err := os.Stat(destPath)
+		if err != nil {
+			return err
+		}
+		if err := plan.add(ChangeDelete, "", destPath, info); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// listEntries returns the names of the non-ignored entries of a directory
+func listEntries(dir string, ignores []string) (map[string]bool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.Name() == ".git" || entry.Name() == ".github" {
+			continue
+		}
+		if !shouldIgnore(filepath.Join(dir, entry.Name()), ignores) {
+			names[entry.Name()] = true
+		}
+	}
+	return names, nil
+}
+
+func (p *Plan) add(changeType ChangeType, source, target string, info os.FileInfo) error {
+	sizePath := source
+	if changeType == ChangeDelete {
+		sizePath = target
+	}
+
+	size := info.Size()
+	if info.IsDir() {
+		var err error
+		if size, err = pathSize(sizePath); err != nil {
+			return err
+		}
+	}
+
+	p.Changes = append(p.Changes, Change{
+		Type:   changeType,
+		Source: source,
+		Target: target,
+		Size:   size,
+		IsDir:  info.IsDir(),
+	})
+	return nil
+}
+
+// filesDiffer reports whether two files have different contents.
+// Files of different sizes differ without being read, otherwise their hashes are compared.
+func filesDiffer(a string, aInfo os.FileInfo, b string, bInfo os.FileInfo, manifest *Manifest) (bool, error) {
+	if aInfo.Size() != bInfo.Size() {
+		return true, nil
+	}
+	aHash, err := manifest.Hash(a)
+	if err != nil {
+		return false, err
+	}
+	bHash, err := manifest.Hash(b)
+	if err != nil {
+		return false, err
+	}
+	return aHash != bHash, nil
+}
+
+// pathSize returns the total size of the regular files under path
+func pathSize(path string) (int64, error) {
+	var size int64
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,164 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanChanges(t *testing.T) {
+	tempDir := t.TempDir()
+	origin := filepath.Join(tempDir, "config", "nvim")
+	dest := filepath.Join(tempDir, "store", "nvim")
+
+	files := map[string]string{
+		filepath.Join(origin, "init.lua"):        "-- new",
+		filepath.Join(origin, "same.lua"):        "-- same",
+		filepath.Join(origin, "lua", "a.lua"):    "-- a",
+		filepath.Join(origin, "swap"):            "file now",
+		filepath.Join(origin, "debug.log"):       "ignored",
+		filepath.Join(dest, "init.lua"):          "-- old",
+		filepath.Join(dest, "same.lua"):          "-- same",
+		filepath.Join(dest, "removed.lua"):       "-- gone",
+		filepath.Join(dest, "swap", "inner.lua"): "dir before",
+	}
+	for path, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	plan, err := PlanChanges(origin, dest, []string{"*.log"})
+	require.NoError(t, err)
+
+	byTarget := make(map[string]Change)
+	for _, change := range plan.Changes {
+		byTarget[change.Target] = change
+	}
+
+	assert.Len(t, plan.Changes, 4)
+	assert.Equal(t, ChangeModify, byTarget[filepath.Join(dest, "init.lua")].Type)
+	assert.Equal(t, ChangeCreate, byTarget[filepath.Join(dest, "lua")].Type)
+	assert.True(t, byTarget[filepath.Join(dest, "lua")].IsDir)
+	assert.Equal(t, int64(len("-- a")), byTarget[filepath.Join(dest, "lua")].Size)
+	assert.Equal(t, ChangeDelete, byTarget[filepath.Join(dest, "removed.lua")].Type)
+	assert.Equal(t, ChangeTypeChange, byTarget[filepath.Join(dest, "swap")].Type)
+	assert.Equal(t, 1, plan.Count(ChangeCreate))
+
+	// Planning must not touch either side
+	content, err := os.ReadFile(filepath.Join(dest, "init.lua"))
+	require.NoError(t, err)
+	assert.Equal(t, "-- old", string(content))
+	_, err = os.Stat(filepath.Join(dest, "removed.lua"))
+	assert.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dest, "lua"))
+	assert.True(t, os.IsNotExist(err))
+
+	// Applying the plan makes dest match origin
+	require.NoError(t, ApplyPlan(plan, []string{"*.log"}))
+	again, err := PlanChanges(origin, dest, []string{"*.log"})
+	require.NoError(t, err)
+	assert.True(t, again.IsEmpty(), "Plan should be empty after applying it, got %+v", again.Changes)
+}
+
+func TestPlanRemoval(t *testing.T) {
+	tempDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "a"), []byte("12345"), 0644))
+
+	plan, err := PlanRemoval(tempDir)
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 1)
+	assert.Equal(t, ChangeDelete, plan.Changes[0].Type)
+	assert.Equal(t, int64(5), plan.TotalSize())
+
+	plan, err = PlanRemoval(filepath.Join(tempDir, "missing"))
+	require.NoError(t, err)
+	assert.True(t, plan.IsEmpty())
+}
+
+func TestPlanFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	origin := filepath.Join(tempDir, "store", "nvim")
+	dest := filepath.Join(tempDir, "config", "nvim")
+
+	for path, content := range map[string]string{
+		filepath.Join(origin, "init.lua"):          "-- new",
+		filepath.Join(origin, "lua", "a.lua"):      "-- a",
+		filepath.Join(origin, "other.lua"):         "-- not listed",
+		filepath.Join(dest, "init.lua"):            "-- old",
+		filepath.Join(dest, "lua", "old", "b.lua"): "-- gone",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	plan, err := PlanFiles(origin, dest, []string{"init.lua", "lua/a.lua", "lua/old/b.lua", "missing.lua"})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 3)
+	assert.Equal(t, ChangeModify, plan.Changes[0].Type)
+	assert.Equal(t, filepath.Join(dest, "init.lua"), plan.Changes[0].Target)
+	assert.Equal(t, ChangeCreate, plan.Changes[1].Type)
+	assert.Equal(t, filepath.Join(dest, "lua", "a.lua"), plan.Changes[1].Target)
+	assert.Equal(t, ChangeDelete, plan.Changes[2].Type)
+	assert.Equal(t, filepath.Join(dest, "lua", "old", "b.lua"), plan.Changes[2].Target)
+
+	// Only the listed files are touched, emptied directories can then be removed
+	require.NoError(t, ApplyPlan(plan, nil))
+	require.NoError(t, RemoveEmptyParents(filepath.Join(dest, "lua", "old", "b.lua"), dest))
+	assert.NoDirExists(t, filepath.Join(dest, "lua", "old"))
+	assert.FileExists(t, filepath.Join(dest, "lua", "a.lua"))
+	assert.NoFileExists(t, filepath.Join(dest, "other.lua"))
+}
+
+func TestPlan_Targets(t *testing.T) {
+	tempDir := t.TempDir()
+	origin := filepath.Join(tempDir, "config", "nvim")
+	dest := filepath.Join(tempDir, "store", "nvim")
+
+	for path, content := range map[string]string{
+		filepath.Join(origin, "init.lua"):        "-- new",
+		filepath.Join(origin, "lua", "a.lua"):    "-- a",
+		filepath.Join(origin, "lua", "sub", "b"): "-- b",
+		filepath.Join(dest, "init.lua"):          "-- old",
+		filepath.Join(dest, "removed.lua"):       "-- gone",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	plan, err := PlanChanges(origin, dest, nil)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"init.lua", "lua/a.lua", "lua/sub/b", "removed.lua"}, plan.Targets(dest, nil))
+
+	// A single file is reported by name
+	plan, err = PlanChanges(filepath.Join(origin, "init.lua"), filepath.Join(dest, "init.lua"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"init.lua"}, plan.Targets(filepath.Join(dest, "init.lua"), nil))
+}
+
+func TestPlan_Excluding(t *testing.T) {
+	tempDir := t.TempDir()
+	origin := filepath.Join(tempDir, "config", "nvim")
+	dest := filepath.Join(tempDir, "store", "nvim")
+
+	for path, content := range map[string]string{
+		filepath.Join(origin, "init.lua"):          "-- new",
+		filepath.Join(origin, "secrets.lua"):       "-- token",
+		filepath.Join(origin, "lua", "plugin.lua"): "-- token",
+		filepath.Join(dest, "init.lua"):            "-- old",
+		filepath.Join(dest, "secrets.lua"):         "-- old token",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	plan, err := PlanChanges(origin, dest, nil)
+	require.NoError(t, err)
+
+	// The new lua directory is left out as a whole
+	filtered := plan.Excluding([]string{filepath.Join(origin, "secrets.lua"), filepath.Join(origin, "lua", "plugin.lua")})
+	assert.Equal(t, []string{"init.lua"}, filtered.Targets(dest, nil))
+	assert.Len(t, plan.Changes, 3)
+}
@@ -2,11 +2,54 @@ package system
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
+
+	"golang.org/x/term"
+)
+
+// PromptPolicy answers the prompts of gart in non-interactive mode
+type PromptPolicy string
+
+const (
+	// PromptFail fails the operation that needs an answer (default)
+	PromptFail PromptPolicy = "fail"
+	// PromptSkip declines: offending files or dotfiles are skipped and nothing is removed
+	PromptSkip PromptPolicy = "skip"
+	// PromptProceed accepts, within the limits that also apply without prompting
+	PromptProceed PromptPolicy = "proceed"
 )
 
+// ErrInputRequired is returned when a prompt is reached in non-interactive mode with the fail policy
+var ErrInputRequired = errors.New("input required in non-interactive mode")
+
+// ParsePromptPolicy validates a prompt policy name, an empty name means fail
+func ParsePromptPolicy(name string) (PromptPolicy, error) {
+	switch PromptPolicy(name) {
+	case "":
+		return PromptFail, nil
+	case PromptFail, PromptSkip, PromptProceed:
+		return PromptPolicy(name), nil
+	default:
+		return "", fmt.Errorf("invalid prompt policy '%s': must be one of: %s, %s, %s",
+			name, PromptFail, PromptSkip, PromptProceed)
+	}
+}
+
+// PromptForConfirmation asks a yes/no question, anything but yes is a no
+func PromptForConfirmation(question string) (bool, error) {
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Printf("%s (y/n): ", question)
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		return false, err
+	}
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "y" || response == "yes", nil
+}
+
 // PromptForGitVersioning asks the user if they want to enable Git versioning
 func PromptForGitVersioning() (bool, error) {
 	reader := bufio.NewReader(os.Stdin)
@@ -18,3 +61,25 @@ func PromptForGitVersioning() (bool, error) {
 	response = strings.TrimSpace(strings.ToLower(response))
 	return response == "y" || response == "yes", nil
 }
+
+// PromptForPassphrase reads a passphrase from the terminal without echoing it
+func PromptForPassphrase(prompt string) (string, error) {
+	fmt.Print(prompt)
+	defer fmt.Println()
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		reader := bufio.NewReader(os.Stdin)
+		passphrase, err := reader.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(passphrase, "\r\n"), nil
+	}
+
+	passphrase, err := term.ReadPassword(fd)
+	if err != nil {
+		return "", err
+	}
+	return string(passphrase), nil
+}
@@ -1,6 +1,10 @@
 package system
 
-import "os"
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
 
 func RemoveDirectory(path string) error {
 	// Remove the directory
@@ -10,3 +14,23 @@ func RemoveDirectory(path string) error {
 
 	return nil
 }
+
+// RemoveEmptyParents removes the directories above path left empty, stopping at root
+func RemoveEmptyParents(path, root string) error {
+	root = filepath.Clean(root)
+	for dir := filepath.Dir(path); strings.HasPrefix(dir, root+string(filepath.Separator)); dir = filepath.Dir(dir) {
+		entries, err := os.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if len(entries) > 0 {
+			return nil
+		}
+		if err := os.Remove(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
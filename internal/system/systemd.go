@@ -0,0 +1,60 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SystemdUserUnitPath returns the location of a systemd user unit
+func SystemdUserUnitPath(name string) (string, error) {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		configHome = filepath.Join(homeDir, ".config")
+	}
+	return filepath.Join(configHome, "systemd", "user", name), nil
+}
+
+// SystemdServiceUnit returns a systemd user service running command, restarted on failure
+func SystemdServiceUnit(description string, command []string) string {
+	args := make([]string, 0, len(command))
+	for _, arg := range command {
+		if strings.ContainsAny(arg, " \t\"'\\") {
+			arg = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(arg) + `"`
+		}
+		args = append(args, arg)
+	}
+
+	return fmt.Sprintf(`[Unit]
+Description=%s
+
+[Service]
+Type=simple
+ExecStart=%s
+Restart=on-failure
+RestartSec=10
+
+[Install]
+WantedBy=default.target
+`, description, strings.Join(args, " "))
+}
+
+// WriteSystemdUserUnit writes a systemd user unit and returns its path
+func WriteSystemdUserUnit(name, content string) (string, error) {
+	path, err := SystemdUserUnitPath(name)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
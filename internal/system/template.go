@@ -0,0 +1,237 @@
+package system
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"text/template"
+)
+
+// TemplateData holds the variables available to dotfile templates
+type TemplateData struct {
+	Hostname string
+	OS       string
+	Arch     string
+	Username string
+	Home     string
+	Profile  string
+	Data     map[string]string
+}
+
+// NewTemplateData returns the template variables of the current machine
+func NewTemplateData(profile string, data map[string]string) TemplateData {
+	hostname, _ := GetHostname()
+	home, _ := os.UserHomeDir()
+
+	username := os.Getenv("USER")
+	if current, err := user.Current(); err == nil {
+		username = current.Username
+	}
+
+	if data == nil {
+		data = make(map[string]string)
+	}
+
+	return TemplateData{
+		Hostname: hostname,
+		OS:       runtime.GOOS,
+		Arch:     runtime.GOARCH,
+		Username: username,
+		Home:     home,
+		Profile:  profile,
+		Data:     data,
+	}
+}
+
+// TemplateConflictError is returned when a locally modified line was generated by a template action
+// and therefore cannot be written back to the template automatically
+type TemplateConflictError struct {
+	Line int
+	Text string
+}
+
+func (e *TemplateConflictError) Error() string {
+	return fmt.Sprintf("line %d (%q) is generated by a template action and was changed locally, edit the template in the store instead", e.Line, e.Text)
+}
+
+// RenderTemplate executes a dotfile template with the given data
+func RenderTemplate(content []byte, data TemplateData) ([]byte, error) {
+	tmpl, err := template.New("dotfile").Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ReverseTemplate computes the template whose rendering gives local.
+// Lines without template actions are taken from local, lines produced by template
+// actions are kept as in tmpl. A TemplateConflictError is returned if a generated line
+// was modified or removed locally.
+func ReverseTemplate(tmpl, local []byte, data TemplateData) ([]byte, error) {
+	rendered, err := RenderTemplate(tmpl, data)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(rendered, local) {
+		return tmpl, nil
+	}
+
+	tmplLines := splitLines(string(tmpl))
+	renderedLines := splitLines(string(rendered))
+
+	// Align the template with its rendering: literal lines are equal on both sides,
+	// template actions only exist in the template, generated lines only in the rendering
+	type segment struct {
+		kind byte // '=' literal, 't' template only, 'r' rendered only
+		t, r int
+	}
+	var segments []segment
+	t, r := 0, 0
+	for _, op := range diffLines(string(tmpl), string(rendered)) {
+		switch op.kind {
+		case ' ':
+			segments = append(segments, segment{kind: '=', t: t, r: r})
+			t++
+			r++
+		case '-':
+			segments = append(segments, segment{kind: 't', t: t})
+			t++
+		case '+':
+			segments = append(segments, segment{kind: 'r', r: r})
+			r++
+		}
+	}
+
+	// Record how local differs from the rendering, per rendered line
+	deleted := make([]bool, len(renderedLines))
+	insertsBefore := make([][]string, len(renderedLines)+1)
+	r = 0
+	for _, op := range diffLines(string(rendered), string(local)) {
+		switch op.kind {
+		case ' ':
+			r++
+		case '-':
+			deleted[r] = true
+			r++
+		case '+':
+			insertsBefore[r] = append(insertsBefore[r], op.text)
+		}
+	}
+
+	var out []string
+	flushed := make([]bool, len(renderedLines)+1)
+	flush := func(r int) {
+		if !flushed[r] {
+			out = append(out, insertsBefore[r]...)
+			flushed[r] = true
+		}
+	}
+
+	nextR := 0
+	for _, seg := range segments {
+		switch seg.kind {
+		case 't':
+			flush(nextR)
+			out = append(out, tmplLines[seg.t])
+		case '=':
+			flush(seg.r)
+			if !deleted[seg.r] {
+				out = append(out, tmplLines[seg.t])
+			}
+			nextR = seg.r + 1
+		case 'r':
+			flush(seg.r)
+			if deleted[seg.r] {
+				return nil, &TemplateConflictError{Line: seg.r + 1, Text: renderedLines[seg.r]}
+			}
+			nextR = seg.r + 1
+		}
+	}
+	flush(len(renderedLines))
+
+	result := strings.Join(out, "\n")
+	if len(out) > 0 && bytes.HasSuffix(local, []byte("\n")) {
+		result += "\n"
+	}
+	return []byte(result), nil
+}
+
+// RenderTree copies src to dst, rendering every text file as a template
+func RenderTree(src, dst string, data TemplateData) error {
+	return transformTree(src, dst, true, func(_ string, content []byte) ([]byte, error) {
+		return RenderTemplate(content, data)
+	})
+}
+
+// ReverseTree copies the local path src to dst, turning each file that has a
+// template counterpart under tmplRoot back into a template. Files without
+// a counterpart are copied as they are.
+func ReverseTree(src, tmplRoot, dst string, data TemplateData) error {
+	return transformTree(src, dst, true, func(rel string, content []byte) ([]byte, error) {
+		tmpl, err := os.ReadFile(filepath.Join(tmplRoot, rel))
+		if os.IsNotExist(err) {
+			return content, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if IsBinary(tmpl) {
+			return content, nil
+		}
+
+		reversed, err := ReverseTemplate(tmpl, content, data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Join(tmplRoot, rel), err)
+		}
+		return reversed, nil
+	})
+}
+
+// transformTree copies src (a file or directory) to dst, passing the content
+// of every regular file through transform, or only of text files when textOnly is set
+func transformTree(src, dst string, textOnly bool, transform func(rel string, content []byte) ([]byte, error)) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if info.IsDir() {
+			if info.Name() == ".git" || info.Name() == ".github" {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if !textOnly || !IsBinary(content) {
+			if content, err = transform(rel, content); err != nil {
+				return err
+			}
+		}
+
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		return os.WriteFile(target, content, info.Mode().Perm())
+	})
+}
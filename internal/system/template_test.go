@@ -0,0 +1,73 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTemplateData() TemplateData {
+	return TemplateData{
+		Hostname: "laptop",
+		OS:       "linux",
+		Username: "user",
+		Data:     map[string]string{"email": "user@example.com"},
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	tmpl := "[user]\n\tname = {{ .Username }}\n\temail = {{ .Data.email }}\n"
+
+	rendered, err := RenderTemplate([]byte(tmpl), testTemplateData())
+	require.NoError(t, err)
+	assert.Equal(t, "[user]\n\tname = user\n\temail = user@example.com\n", string(rendered))
+
+	_, err = RenderTemplate([]byte("{{ .Data.missing }}"), testTemplateData())
+	assert.Error(t, err, "Unknown data keys must fail instead of rendering <no value>")
+}
+
+func TestReverseTemplate(t *testing.T) {
+	tmpl := "[user]\n\temail = {{ .Data.email }}\n[core]\n\teditor = vim\n"
+
+	tests := []struct {
+		name    string
+		local   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "Unchanged rendering keeps the template",
+			local: "[user]\n\temail = user@example.com\n[core]\n\teditor = vim\n",
+			want:  tmpl,
+		},
+		{
+			name:  "Literal line change is written back",
+			local: "[user]\n\temail = user@example.com\n[core]\n\teditor = nvim\n",
+			want:  "[user]\n\temail = {{ .Data.email }}\n[core]\n\teditor = nvim\n",
+		},
+		{
+			name:  "Added lines are written back",
+			local: "[user]\n\temail = user@example.com\n[core]\n\teditor = vim\n\tpager = less\n",
+			want:  "[user]\n\temail = {{ .Data.email }}\n[core]\n\teditor = vim\n\tpager = less\n",
+		},
+		{
+			name:    "Changing a generated line is a conflict",
+			local:   "[user]\n\temail = other@example.com\n[core]\n\teditor = vim\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReverseTemplate([]byte(tmpl), []byte(tt.local), testTemplateData())
+			if tt.wantErr {
+				var conflict *TemplateConflictError
+				assert.ErrorAs(t, err, &conflict)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// diffContextLines is the number of unchanged lines shown around each hunk
+const diffContextLines = 3
+
+// FileDiff holds the differences of a single file between its current (old)
+// and incoming (new) content. Unified is empty for binary files.
+type FileDiff struct {
+	OldPath string
+	NewPath string
+	Change  ChangeType
+	Binary  bool
+	Added   int
+	Removed int
+	Unified string
+}
+
+type lineOp struct {
+	kind byte // ' ', '-' or '+'
+	text string
+}
+
+// DiffContent computes the unified diff between two versions of a file.
+// An empty oldPath or newPath means the file is created or deleted.
+func DiffContent(oldPath, newPath string, oldContent, newContent []byte) FileDiff {
+	diff := FileDiff{OldPath: oldPath, NewPath: newPath, Change: ChangeModify}
+	switch {
+	case oldPath == "":
+		diff.Change = ChangeCreate
+	case newPath == "":
+		diff.Change = ChangeDelete
+	}
+
+	if IsBinary(oldContent) || IsBinary(newContent) {
+		diff.Binary = true
+		return diff
+	}
+
+	ops := diffLines(string(oldContent), string(newContent))
+	for _, op := range ops {
+		switch op.kind {
+		case '+':
+			diff.Added++
+		case '-':
+			diff.Removed++
+		}
+	}
+
+	diff.Unified = renderHunks(ops)
+	return diff
+}
+
+// DiffPlan computes the file diffs of every change in a plan.
+// For each change the target is the old version and the source the new one.
+func DiffPlan(plan *Plan, ignores []string) ([]FileDiff, error) {
+	var diffs []FileDiff
+	for _, change := range plan.Changes {
+		var err error
+		switch change.Type {
+		case ChangeCreate:
+			diffs, err = appendTreeDiffs(diffs, change.Source, change.Target, ignores, true)
+		case ChangeDelete:
+			diffs, err = appendTreeDiffs(diffs, change.Target, change.Target, ignores, false)
+		case ChangeModify:
+			var fileDiff FileDiff
+			fileDiff, err = diffFilePair(change.Target, change.Source)
+			diffs = append(diffs, fileDiff)
+		case ChangeTypeChange:
+			if diffs, err = appendTreeDiffs(diffs, change.Target, change.Target, ignores, false); err == nil {
+				diffs, err = appendTreeDiffs(diffs, change.Source, change.Target, ignores, true)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return diffs, nil
+}
+
+// appendTreeDiffs adds a create (or delete) diff for every file under root
+func appendTreeDiffs(diffs []FileDiff, root, target string, ignores []string, create bool) ([]FileDiff, error) {
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" || info.Name() == ".github" || (path != root && shouldIgnore(path, ignores)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() || shouldIgnore(path, ignores) {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		targetPath := filepath.Join(target, rel)
+
+		if create {
+			diffs = append(diffs, DiffContent("", targetPath, nil, content))
+		} else {
+			diffs = append(diffs, DiffContent(targetPath, "", content, nil))
+		}
+		return nil
+	})
+	return diffs, err
+}
+
+func diffFilePair(oldPath, newPath string) (FileDiff, error) {
+	oldContent, err := os.ReadFile(oldPath)
+	if err != nil {
+		return FileDiff{}, err
+	}
+	newContent, err := os.ReadFile(newPath)
+	if err != nil {
+		return FileDiff{}, err
+	}
+	return DiffContent(oldPath, newPath, oldContent, newContent), nil
+}
+
+// Path returns the path the diff applies to
+func (d FileDiff) Path() string {
+	if d.OldPath != "" {
+		return d.OldPath
+	}
+	return d.NewPath
+}
+
+// Header returns the unified diff file header
+func (d FileDiff) Header() string {
+	oldName, newName := "/dev/null", "/dev/null"
+	if d.OldPath != "" {
+		oldName = "a" + filepath.ToSlash(d.OldPath)
+	}
+	if d.NewPath != "" {
+		newName = "b" + filepath.ToSlash(d.Path())
+	}
+	return fmt.Sprintf("--- %s\n+++ %s\n", oldName, newName)
+}
+
+// diffLines returns the line level edit script between two texts
+func diffLines(oldText, newText string) []lineOp {
+	dmp := diffmatchpatch.New()
+	oldChars, newChars, lines := dmp.DiffLinesToChars(oldText, newText)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(oldChars, newChars, false), lines)
+
+	var ops []lineOp
+	for _, d := range diffs {
+		kind := byte(' ')
+		switch d.Type {
+		case diffmatchpatch.DiffInsert:
+			kind = '+'
+		case diffmatchpatch.DiffDelete:
+			kind = '-'
+		}
+		for _, line := range splitLines(d.Text) {
+			ops = append(ops, lineOp{kind: kind, text: line})
+		}
+	}
+	return ops
+}
+
+// renderHunks formats an edit script as unified diff hunks
+func renderHunks(ops []lineOp) string {
+	// Line numbers before each op, on the old and new side
+	oldBefore := make([]int, len(ops)+1)
+	newBefore := make([]int, len(ops)+1)
+	for i, op := range ops {
+		oldBefore[i+1], newBefore[i+1] = oldBefore[i], newBefore[i]
+		if op.kind != '+' {
+			oldBefore[i+1]++
+		}
+		if op.kind != '-' {
+			newBefore[i+1]++
+		}
+	}
+
+	// Merge the context windows around changed lines into hunk ranges
+	var ranges [][2]int
+	for i, op := range ops {
+		if op.kind == ' ' {
+			continue
+		}
+		start, end := max(i-diffContextLines, 0), min(i+diffContextLines+1, len(ops))
+		if len(ranges) > 0 && start <= ranges[len(ranges)-1][1] {
+			ranges[len(ranges)-1][1] = end
+			continue
+		}
+		ranges = append(ranges, [2]int{start, end})
+	}
+
+	var b strings.Builder
+	for _, r := range ranges {
+		oldCount := oldBefore[r[1]] - oldBefore[r[0]]
+		newCount := newBefore[r[1]] - newBefore[r[0]]
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n",
+			hunkRange(oldBefore[r[0]], oldCount),
+			hunkRange(newBefore[r[0]], newCount))
+		for _, op := range ops[r[0]:r[1]] {
+			b.WriteByte(op.kind)
+			b.WriteString(op.text)
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+func hunkRange(before, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// IsBinary reports whether content looks like binary data,
+// using the presence of null bytes in the first 8KB
+func IsBinary(content []byte) bool {
+	checkLen := min(len(content), 8192)
+	for i := 0; i < checkLen; i++ {
+		if content[i] == 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffContent(t *testing.T) {
+	tests := []struct {
+		name        string
+		oldContent  string
+		newContent  string
+		wantAdded   int
+		wantRemoved int
+		wantUnified string
+	}{
+		{
+			name:        "Single line change keeps context",
+			oldContent:  "a\nb\nc\nd\ne\nf\ng\nh\n",
+			newContent:  "a\nb\nc\nd\nE\nf\ng\nh\n",
+			wantAdded:   1,
+			wantRemoved: 1,
+			wantUnified: "@@ -2,7 +2,7 @@\n b\n c\n d\n-e\n+E\n f\n g\n h\n",
+		},
+		{
+			name:        "Appended lines",
+			oldContent:  "one\n",
+			newContent:  "one\ntwo\nthree\n",
+			wantAdded:   2,
+			wantRemoved: 0,
+			wantUnified: "@@ -1,1 +1,3 @@\n one\n+two\n+three\n",
+		},
+		{
+			name:        "Identical content",
+			oldContent:  "same\n",
+			newContent:  "same\n",
+			wantUnified: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := DiffContent("/store/f", "/local/f", []byte(tt.oldContent), []byte(tt.newContent))
+			assert.False(t, diff.Binary)
+			assert.Equal(t, tt.wantAdded, diff.Added)
+			assert.Equal(t, tt.wantRemoved, diff.Removed)
+			assert.Equal(t, tt.wantUnified, diff.Unified)
+		})
+	}
+}
+
+func TestDiffContent_Binary(t *testing.T) {
+	diff := DiffContent("/store/img", "/local/img", []byte{0x89, 0x00, 0x01}, []byte{0x89, 0x00, 0x02})
+	assert.True(t, diff.Binary)
+	assert.Empty(t, diff.Unified)
+}
+
+func TestDiffPlan(t *testing.T) {
+	tempDir := t.TempDir()
+	local := filepath.Join(tempDir, "local")
+	store := filepath.Join(tempDir, "store")
+	require.NoError(t, os.MkdirAll(filepath.Join(local, "sub"), 0755))
+	require.NoError(t, os.MkdirAll(store, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(local, "a.conf"), []byte("x = 2\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(store, "a.conf"), []byte("x = 1\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(local, "sub", "new.conf"), []byte("y\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(store, "old.conf"), []byte("z\n"), 0644))
+
+	plan, err := PlanSync(local, store, nil, false)
+	require.NoError(t, err)
+
+	diffs, err := DiffPlan(plan, nil)
+	require.NoError(t, err)
+	require.Len(t, diffs, 3)
+
+	byPath := make(map[string]FileDiff)
+	for _, diff := range diffs {
+		byPath[diff.Path()] = diff
+	}
+
+	modified := byPath[filepath.Join(store, "a.conf")]
+	assert.Equal(t, ChangeModify, modified.Change)
+	assert.Equal(t, "@@ -1,1 +1,1 @@\n-x = 1\n+x = 2\n", modified.Unified)
+
+	created := byPath[filepath.Join(store, "sub", "new.conf")]
+	assert.Equal(t, ChangeCreate, created.Change)
+	assert.Equal(t, 1, created.Added)
+
+	deleted := byPath[filepath.Join(store, "old.conf")]
+	assert.Equal(t, ChangeDelete, deleted.Change)
+	assert.Equal(t, 1, deleted.Removed)
+	assert.Contains(t, deleted.Header(), "+++ /dev/null")
+}
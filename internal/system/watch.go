@@ -0,0 +1,162 @@
+package system
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// WatchTarget is a dotfile path watched for changes
+type WatchTarget struct {
+	Name    string
+	Path    string
+	Ignores []string
+}
+
+// Watcher reports the dotfiles whose files changed once a burst of writes settled
+type Watcher struct {
+	watcher  *fsnotify.Watcher
+	targets  []WatchTarget
+	debounce time.Duration
+}
+
+// NewWatcher creates a watcher calling back after debounce elapsed without new changes
+func NewWatcher(debounce time.Duration) (*Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	return &Watcher{watcher: watcher, debounce: debounce}, nil
+}
+
+// Add watches a dotfile. Directories are watched recursively, except ignored paths and
+// .git directories. Files are watched through their parent directory so that editors
+// replacing the file on save are noticed.
+func (w *Watcher) Add(target WatchTarget) error {
+	target.Path = filepath.Clean(target.Path)
+	info, err := os.Stat(target.Path)
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		if err := w.addTree(target.Path, target.Ignores); err != nil {
+			return err
+		}
+	} else if err := w.watcher.Add(filepath.Dir(target.Path)); err != nil {
+		return err
+	}
+
+	w.targets = append(w.targets, target)
+	return nil
+}
+
+// addTree watches a directory and its subdirectories
+func (w *Watcher) addTree(root string, ignores []string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != root && (isVCSDir(d.Name()) || shouldIgnore(path, ignores)) {
+			return filepath.SkipDir
+		}
+		return w.watcher.Add(path)
+	})
+}
+
+// Run calls onChange with the names of the changed dotfiles until ctx is done. Changes
+// arriving while onChange runs are reported by the next call. Watch errors are passed
+// to onError and do not stop the watcher.
+func (w *Watcher) Run(ctx context.Context, onChange func(names []string), onError func(error)) error {
+	pending := make(map[string]bool)
+	timer := time.NewTimer(w.debounce)
+	timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return nil
+			}
+			target, ok := w.match(event.Name)
+			if !ok {
+				continue
+			}
+
+			// New directories of a watched tree are watched too
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() && event.Name != target.Path {
+					if err := w.addTree(event.Name, target.Ignores); err != nil {
+						onError(err)
+					}
+				}
+			}
+
+			pending[target.Name] = true
+			timer.Reset(w.debounce)
+
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return nil
+			}
+			onError(err)
+
+		case <-timer.C:
+			names := make([]string, 0, len(pending))
+			for name := range pending {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			clear(pending)
+			onChange(names)
+		}
+	}
+}
+
+// Close stops watching every path
+func (w *Watcher) Close() error {
+	return w.watcher.Close()
+}
+
+// match returns the target a changed path belongs to, unless the path is ignored
+func (w *Watcher) match(path string) (WatchTarget, bool) {
+	path = filepath.Clean(path)
+	for _, target := range w.targets {
+		if path != target.Path && !strings.HasPrefix(path, target.Path+string(filepath.Separator)) {
+			continue
+		}
+
+		rel, err := filepath.Rel(target.Path, path)
+		if err != nil {
+			continue
+		}
+		for _, part := range strings.Split(filepath.ToSlash(rel), "/") {
+			if isVCSDir(part) {
+				return WatchTarget{}, false
+			}
+		}
+		if path != target.Path && shouldIgnore(path, target.Ignores) {
+			return WatchTarget{}, false
+		}
+		return target, true
+	}
+	return WatchTarget{}, false
+}
+
+// isVCSDir reports whether a directory name is one of the directories never synced
+func isVCSDir(name string) bool {
+	return name == ".git" || name == ".github"
+}
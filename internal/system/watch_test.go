@@ -0,0 +1,63 @@
+package system
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher_Run(t *testing.T) {
+	tempDir := t.TempDir()
+	nvim := filepath.Join(tempDir, "nvim")
+	zshrc := filepath.Join(tempDir, ".zshrc")
+	require.NoError(t, os.MkdirAll(filepath.Join(nvim, "lua"), 0755))
+	require.NoError(t, os.WriteFile(zshrc, []byte("export A=1\n"), 0644))
+
+	watcher, err := NewWatcher(100 * time.Millisecond)
+	require.NoError(t, err)
+	defer func() { _ = watcher.Close() }()
+	require.NoError(t, watcher.Add(WatchTarget{Name: "nvim", Path: nvim, Ignores: []string{"*.log"}}))
+	require.NoError(t, watcher.Add(WatchTarget{Name: "zshrc", Path: zshrc}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := make(chan []string, 4)
+	go func() {
+		_ = watcher.Run(ctx, func(names []string) { changes <- names }, func(err error) { t.Error(err) })
+	}()
+
+	next := func(timeout time.Duration) []string {
+		select {
+		case names := <-changes:
+			return names
+		case <-time.After(timeout):
+			return nil
+		}
+	}
+
+	// A burst of writes to both dotfiles is reported once
+	for i := 0; i < 5; i++ {
+		require.NoError(t, os.WriteFile(filepath.Join(nvim, "lua", "a.lua"), []byte(strings.Repeat("-", i)), 0644))
+		require.NoError(t, os.WriteFile(zshrc, []byte(strings.Repeat("#", i)), 0644))
+	}
+	assert.Equal(t, []string{"nvim", "zshrc"}, next(2*time.Second))
+
+	// Ignored files and unrelated files next to a watched file are not reported
+	require.NoError(t, os.WriteFile(filepath.Join(nvim, "debug.log"), []byte("log"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, ".bashrc"), []byte("bash"), 0644))
+	assert.Nil(t, next(500*time.Millisecond))
+}
+
+func TestSystemdServiceUnit(t *testing.T) {
+	unit := SystemdServiceUnit("Gart dotfiles watcher", []string{"/usr/bin/gart", "watch", "--log-file", "/home/me/my logs/watch.log"})
+
+	assert.Contains(t, unit, "Description=Gart dotfiles watcher\n")
+	assert.Contains(t, unit, `ExecStart=/usr/bin/gart watch --log-file "/home/me/my logs/watch.log"`+"\n")
+	assert.Contains(t, unit, "WantedBy=default.target\n")
+}
@@ -8,37 +8,52 @@ import (
 	"github.com/bnema/gart/internal/security"
 )
 
-func RunAddDotfileView(app *app.App, path string, dotfileName string, ignores []string) {
+// RunAddDotfileView adds a dotfile to the store and returns false if it could not be added
+func RunAddDotfileView(app *app.App, path string, dotfileName string, ignores []string, symlink, template, encrypt bool) bool {
 	path = app.ExpandHomeDir(path)
 	cleanedPath := filepath.Clean(path)
 
 	fmt.Printf("Adding dotfile %s... ", dotfileName)
 
+	if err := app.RunPreAddHooks(dotfileName, cleanedPath); err != nil {
+		fmt.Println(errorStyle.Render("Error!"))
+		fmt.Println(err)
+		return false
+	}
+
 	// Create security context and scan path
 	securityConfig := security.DefaultSecurityConfig()
-	securityCtx := security.NewSecurityContext(securityConfig)
+	securityCtx, err := app.NewSecurityContext(securityConfig)
 
-	// Scan for security issues before adding
-	report, err := securityCtx.ScanPath(cleanedPath, ignores)
+	// Scan for security issues before adding, encrypted dotfiles are safe to store
+	report := &security.ScanReport{}
+	if err == nil && !encrypt {
+		report, err = securityCtx.ScanPath(cleanedPath, ignores)
+	}
 	if err != nil {
 		fmt.Println(errorStyle.Render("Security scan failed!"))
 		fmt.Println("Error:", err)
-		return
+		return false
 	}
 
 	// Handle security findings interactively
 	if report.TotalFindings > 0 {
 		DisplaySecurityFindings(report)
 
-		proceed, _, err := securityCtx.InteractivePrompt(report)
+		proceed, _, skipFiles, err := securityDecision(app, securityCtx, report)
 		if err != nil {
 			fmt.Println(errorStyle.Render("Error!"))
 			fmt.Println("Security check failed:", err)
-			return
+			return false
 		}
 		if !proceed {
 			fmt.Println("⚠️  Add operation cancelled due to security concerns")
-			return
+			return false
+		}
+		if len(skipFiles) > 0 {
+			displaySkippedFiles(skipFiles)
+			fmt.Println("Add operation skipped, nothing was stored")
+			return true
 		}
 
 		// Note: For add command, we don't use skipAll flag since it's a single operation
@@ -46,21 +61,44 @@ func RunAddDotfileView(app *app.App, path string, dotfileName string, ignores []
 	}
 
 	var addErr error
-	if app.IsDir(path) {
+	encryptedAdded := false
+	if symlink {
+		addErr = app.AddDotfileSymlink(cleanedPath, dotfileName, ignores)
+	} else if encrypt {
+		addErr = app.AddDotfileEncrypted(cleanedPath, dotfileName, ignores)
+		encryptedAdded = addErr == nil
+	} else if app.IsDir(path) {
 		addErr = addDotfileDir(app, cleanedPath, dotfileName, ignores)
 	} else {
 		addErr = addDotfileFile(app, cleanedPath, dotfileName, ignores)
 	}
 
+	if addErr == nil && template {
+		addErr = app.SetDotfileTemplate(dotfileName, true)
+	}
+
+	if addErr == nil && !symlink {
+		addErr = app.RecordSync(dotfileName, cleanedPath, app.StorePathFor(dotfileName, cleanedPath))
+	}
+
+	// An encrypted dotfile is either fully added or not at all
+	if addErr != nil && encryptedAdded {
+		if err := app.DiscardDotfile(dotfileName, cleanedPath); err != nil {
+			addErr = fmt.Errorf("%w (cleanup failed: %v)", addErr, err)
+		}
+	}
+
 	if addErr != nil {
 		fmt.Println(errorStyle.Render("Error!"))
 		fmt.Println(addErr)
-		return
+		return false
 	}
 
 	// Create commit message with security status
 	commitMsg := fmt.Sprintf("Add %s", dotfileName)
-	if report.TotalFindings > 0 {
+	if encrypt {
+		commitMsg += " (security: encrypted)"
+	} else if report.TotalFindings > 0 {
 		commitMsg += fmt.Sprintf(" (security: %d findings, risk: %s)", report.TotalFindings, report.HighestRisk)
 	} else {
 		commitMsg += " (security: clean)"
@@ -69,10 +107,11 @@ func RunAddDotfileView(app *app.App, path string, dotfileName string, ignores []
 	if err := app.GitCommitChanges("Add", commitMsg); err != nil {
 		fmt.Println(errorStyle.Render("Error!"))
 		fmt.Println("Error committing changes:", err)
-		return
+		return false
 	}
 
 	fmt.Println(successStyle.Render("Success!"))
+	return true
 }
 
 func addDotfileDir(app *app.App, cleanedPath, dotfileName string, ignores []string) error {
@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/bnema/gart/internal/app"
+)
+
+// RunBackupListView prints the available backups, newest first
+func RunBackupListView(app *app.App) bool {
+	backups, err := app.ListBackups()
+	if err != nil {
+		fmt.Printf("%s\n", errorStyle.Render(fmt.Sprintf("Error listing backups: %v", err)))
+		return false
+	}
+
+	if len(backups) == 0 {
+		fmt.Println("No backups found.")
+		return true
+	}
+
+	for _, backup := range backups {
+		fmt.Printf("%s  %s  %s\n",
+			boldStyle.Render(backup.ID),
+			backup.CreatedAt.Format("2006-01-02 15:04:05"),
+			unchangedStyle.Render(fmt.Sprintf("%s, %s, %d path(s)", backup.Dotfile, backup.Reason, len(backup.Paths))),
+		)
+		for _, path := range backup.Paths {
+			fmt.Printf("  └─ %s\n", path)
+		}
+	}
+	return true
+}
+
+// RunBackupRestoreView restores a backup to its original locations
+func RunBackupRestoreView(app *app.App, id string) bool {
+	fmt.Printf("Restoring backup %s... ", id)
+
+	backup, err := app.RestoreBackup(id)
+	if err != nil {
+		fmt.Println(errorStyle.Render("Error!"))
+		fmt.Println(err)
+		return false
+	}
+
+	fmt.Println(successStyle.Render(fmt.Sprintf("Success! (%d path(s) restored)", len(backup.Paths))))
+	return true
+}
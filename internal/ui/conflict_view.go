@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bnema/gart/internal/app"
+	"github.com/bnema/gart/internal/system"
+)
+
+// conflictResolution lists the conflicts of a dotfile and returns the configured resolution
+// or asks for one. An empty resolution skips the dotfile, app.ErrSyncAborted means the
+// user aborted.
+func conflictResolution(a *app.App, name string, conflicts []system.Conflict) (string, error) {
+	DisplayConflicts(name, conflicts)
+
+	if a.ConflictResolution != "" {
+		return a.ConflictResolution, nil
+	}
+	if a.NonInteractive() {
+		if a.PromptPolicy == system.PromptFail {
+			return "", fmt.Errorf("conflicts need a decision, pass --resolve to choose one: %w", system.ErrInputRequired)
+		}
+		// Neither side can be overwritten without a choice
+		fmt.Println(alertStyle.Render(fmt.Sprintf(
+			"Skipping '%s', run 'gart sync %s --resolve <local|store|merge|markers>' to resolve it.", name, name)))
+		return "", nil
+	}
+
+	resolution, err := promptConflictResolution()
+	if err != nil {
+		return "", fmt.Errorf("error reading choice: %w", err)
+	}
+	if resolution == "" {
+		return "", app.ErrSyncAborted
+	}
+	return resolution, nil
+}
+
+// DisplayConflicts lists the files changed on both sides of a dotfile
+func DisplayConflicts(name string, conflicts []system.Conflict) {
+	fmt.Printf("%s\n", alertStyle.Render(fmt.Sprintf(
+		"'%s' changed both locally and in the store since the last sync:", name)))
+	for _, conflict := range conflicts {
+		fmt.Printf("  %s\n", boldStyle.Render(conflict.Local))
+	}
+}
+
+// promptConflictResolution asks how to resolve a conflict and returns the chosen resolution,
+// or an empty string to abort
+func promptConflictResolution() (string, error) {
+	reader := bufio.NewReader(os.Stdin)
+
+	for {
+		fmt.Print("\nOptions:\n")
+		fmt.Printf("  keep [%s]ocal version (overwrite the store)\n", boldStyle.Render("l"))
+		fmt.Printf("  keep [%s]tore version (overwrite local files)\n", boldStyle.Render("s"))
+		fmt.Printf("  [%s]erge in $EDITOR\n", boldStyle.Render("m"))
+		fmt.Printf("  [%s]rite conflict markers (local versions are backed up), resolve later\n", boldStyle.Render("w"))
+		fmt.Printf("  [%s]bort\n", boldStyle.Render("a"))
+		fmt.Print("\nYour choice: ")
+
+		input, err := reader.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+
+		switch strings.ToLower(strings.TrimSpace(input)) {
+		case "l", "local":
+			return app.ResolveLocal, nil
+		case "s", "store":
+			return app.ResolveStore, nil
+		case "m", "merge":
+			return app.ResolveMerge, nil
+		case "w", "markers":
+			return app.ResolveMarkers, nil
+		case "a", "abort":
+			return "", nil
+		default:
+			fmt.Println("Invalid choice, please try again.")
+		}
+	}
+}
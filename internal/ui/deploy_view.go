@@ -0,0 +1,28 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/bnema/gart/internal/app"
+)
+
+// RunDeployView deploys the given dotfiles from the store and prints a result per entry.
+// It returns false if at least one dotfile failed to deploy.
+func RunDeployView(app *app.App, names []string) bool {
+	if len(app.Config.Dotfiles) == 0 {
+		fmt.Println("No dotfiles found. Please add some dotfiles first.")
+		return true
+	}
+
+	ok := true
+	for _, result := range app.DeployDotfiles(names) {
+		fmt.Print(changedStyle.Render(fmt.Sprintf("Deploying '%s'...", result.Name)))
+		if result.Err != nil {
+			fmt.Printf(" %s\n", errorStyle.Render(fmt.Sprintf("Error: %v", result.Err)))
+			ok = false
+			continue
+		}
+		fmt.Printf(" %s\n", successStyle.Render(fmt.Sprintf("Success! (%s)", result.LocalPath)))
+	}
+	return ok
+}
@@ -0,0 +1,121 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bnema/gart/internal/app"
+	"github.com/bnema/gart/internal/system"
+)
+
+// RunDiffView prints the pending changes between the local path and the store
+// of the given dotfiles, or of every dotfile when names is empty
+func RunDiffView(app *app.App, names []string, stat bool) bool {
+	if len(names) == 0 {
+		for name := range app.Config.Dotfiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	ok := true
+	for _, name := range names {
+		path, found := app.Config.Dotfiles[name]
+		if !found {
+			fmt.Printf("Dotfile '%s' not found.\n", name)
+			ok = false
+			continue
+		}
+
+		diffs, err := dotfileDiffs(app, name, path)
+		if err != nil {
+			fmt.Printf("%s\n", errorStyle.Render(fmt.Sprintf("Error comparing '%s': %v", name, err)))
+			ok = false
+			continue
+		}
+
+		if len(diffs) == 0 {
+			continue
+		}
+
+		if stat {
+			DisplayDiffStat(name, diffs)
+		} else {
+			DisplayDiffs(diffs)
+		}
+	}
+	return ok
+}
+
+// dotfileDiffs computes the file diffs a sync of the dotfile would apply
+func dotfileDiffs(app *app.App, name, path string) ([]system.FileDiff, error) {
+	if app.IsSymlinkMode(name) {
+		return nil, nil
+	}
+
+	path = app.ExpandHomeDir(path)
+	ignores := app.Config.DotfilesIgnores[name]
+	reverse := app.Config.Settings.ReverseSyncMode
+
+	// Encrypted dotfiles are always compared on their decrypted store content
+	storeIsOrigin := reverse || app.IsEncrypted(name)
+	localSide, storeSide, cleanup, err := app.SyncSides(name, path, app.StorePathFor(name, path), storeIsOrigin)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	plan, err := system.PlanSync(localSide, storeSide, ignores, reverse)
+	if err != nil {
+		return nil, err
+	}
+	return system.DiffPlan(plan, ignores)
+}
+
+// DisplayDiffs prints unified diffs with coloured added and removed lines
+func DisplayDiffs(diffs []system.FileDiff) {
+	for _, diff := range diffs {
+		fmt.Print(boldStyle.Render(strings.TrimSuffix(diff.Header(), "\n")) + "\n")
+		if diff.Binary {
+			fmt.Println(unchangedStyle.Render("Binary files differ"))
+			continue
+		}
+
+		for _, line := range strings.Split(strings.TrimSuffix(diff.Unified, "\n"), "\n") {
+			switch {
+			case strings.HasPrefix(line, "@@"):
+				fmt.Println(scanningStyle.Render(line))
+			case strings.HasPrefix(line, "+"):
+				fmt.Println(successStyle.Render(line))
+			case strings.HasPrefix(line, "-"):
+				fmt.Println(errorStyle.Render(line))
+			default:
+				fmt.Println(line)
+			}
+		}
+	}
+}
+
+// DisplayDiffStat prints a per-file summary of added and removed lines
+func DisplayDiffStat(name string, diffs []system.FileDiff) {
+	fmt.Println(boldStyle.Render(name))
+
+	added, removed := 0, 0
+	for _, diff := range diffs {
+		if diff.Binary {
+			fmt.Printf(" %s | %s\n", diff.Path(), unchangedStyle.Render("Bin"))
+			continue
+		}
+		added += diff.Added
+		removed += diff.Removed
+		fmt.Printf(" %s | %d %s%s\n",
+			diff.Path(),
+			diff.Added+diff.Removed,
+			successStyle.Render(strings.Repeat("+", min(diff.Added, 40))),
+			errorStyle.Render(strings.Repeat("-", min(diff.Removed, 40))),
+		)
+	}
+
+	fmt.Println(unchangedStyle.Render(fmt.Sprintf(" %d files changed, %d insertions(+), %d deletions(-)", len(diffs), added, removed)))
+}
@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bnema/gart/internal/app"
+	"github.com/bnema/gart/internal/git"
+	"github.com/bnema/gart/internal/system"
+)
+
+// historyDateFormat is the layout of commit dates in the history views
+const historyDateFormat = "2006-01-02 15:04"
+
+// RunLogView prints the commits that touched a dotfile, or the whole store when name is empty
+func RunLogView(app *app.App, name string, limit int) bool {
+	commits, err := app.DotfileLog(name, limit)
+	if err != nil {
+		fmt.Printf("%s\n", errorStyle.Render(fmt.Sprintf("Error reading history: %v", err)))
+		return false
+	}
+
+	if len(commits) == 0 {
+		fmt.Println(unchangedStyle.Render("No history found."))
+		return true
+	}
+
+	for _, commit := range commits {
+		fmt.Printf("%s %s %s %s\n",
+			alertStyle.Render(commit.ShortHash()),
+			unchangedStyle.Render(commit.Date.Local().Format(historyDateFormat)),
+			scanningStyle.Render("("+commit.Branch+")"),
+			commit.Subject(),
+		)
+	}
+	return true
+}
+
+// RunShowView prints a commit and the diff of the changes it made to a dotfile,
+// or to the whole store when name is empty
+func RunShowView(app *app.App, rev, name string) bool {
+	info, files, err := app.ShowRevision(rev, name)
+	if err != nil {
+		fmt.Printf("%s\n", errorStyle.Render(fmt.Sprintf("Error reading revision '%s': %v", rev, err)))
+		return false
+	}
+
+	fmt.Println(alertStyle.Render("commit " + info.Hash))
+	if info.Branch != "" {
+		fmt.Printf("Branch: %s\n", info.Branch)
+	}
+	fmt.Printf("Author: %s\n", info.Author)
+	fmt.Printf("Date:   %s\n\n", info.Date.Local().Format(historyDateFormat))
+	for _, line := range strings.Split(strings.TrimSpace(info.Message), "\n") {
+		fmt.Printf("    %s\n", line)
+	}
+	fmt.Println()
+
+	DisplayDiffs(fileChangeDiffs(files))
+	return true
+}
+
+// fileChangeDiffs converts the changes read from the store history into displayable diffs
+func fileChangeDiffs(files []git.FileChange) []system.FileDiff {
+	diffs := make([]system.FileDiff, 0, len(files))
+	for _, file := range files {
+		oldPath, newPath := "/"+file.Path, "/"+file.Path
+		if file.From == nil {
+			oldPath = ""
+		}
+		if file.To == nil {
+			newPath = ""
+		}
+		diffs = append(diffs, system.DiffContent(oldPath, newPath, file.From, file.To))
+	}
+	return diffs
+}
@@ -0,0 +1,398 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/bnema/gart/internal/app"
+	"github.com/bnema/gart/internal/config"
+	"github.com/bnema/gart/internal/security"
+)
+
+// JSONSchemaVersion is the version of the documents printed with --output json. It is
+// increased when a field is removed or changes meaning, new fields may be added at any time.
+const JSONSchemaVersion = 1
+
+// jsonHeader starts every JSON document
+type jsonHeader struct {
+	SchemaVersion int    `json:"schema_version"`
+	Command       string `json:"command"`
+}
+
+type jsonListDotfile struct {
+	Name      string   `json:"name"`
+	Path      string   `json:"path"`
+	LocalPath string   `json:"local_path"`
+	StorePath string   `json:"store_path"`
+	Mode      string   `json:"mode"`
+	Template  bool     `json:"template"`
+	Encrypted bool     `json:"encrypted"`
+	Ignores   []string `json:"ignores"`
+}
+
+type jsonList struct {
+	jsonHeader
+	Profile  string            `json:"profile,omitempty"`
+	Dotfiles []jsonListDotfile `json:"dotfiles"`
+}
+
+type jsonChange struct {
+	Type string `json:"type"`
+	Path string `json:"path"`
+}
+
+type jsonFinding struct {
+	File       string   `json:"file"`
+	Line       int      `json:"line,omitempty"`
+	Type       string   `json:"type"`
+	Risk       string   `json:"risk"`
+	Confidence float64  `json:"confidence"`
+	Value      string   `json:"value"`
+	Reasons    []string `json:"reasons,omitempty"`
+}
+
+type jsonSecurity struct {
+	ScannedFiles  int           `json:"scanned_files"`
+	SkippedFiles  int           `json:"skipped_files"`
+	TotalFindings int           `json:"total_findings"`
+	HighestRisk   string        `json:"highest_risk"`
+	Findings      []jsonFinding `json:"findings"`
+	// AcceptedFindings counts the findings accepted in the baseline, left out of Findings
+	AcceptedFindings int `json:"accepted_findings"`
+	// AllowedFindings counts the findings silenced by gart:allow comments
+	AllowedFindings int `json:"allowed_findings"`
+}
+
+type jsonSyncDotfile struct {
+	Name      string        `json:"name"`
+	LocalPath string        `json:"local_path"`
+	StorePath string        `json:"store_path"`
+	Direction string        `json:"direction"`
+	Status    string        `json:"status"`
+	Reason    string        `json:"reason,omitempty"`
+	Changes   []jsonChange  `json:"changes"`
+	Commit    string        `json:"commit,omitempty"`
+	Backup    string        `json:"backup,omitempty"`
+	Security  *jsonSecurity `json:"security,omitempty"`
+	// ExcludedFiles are the files with security findings left out by the skip policy
+	ExcludedFiles []string `json:"excluded_files,omitempty"`
+	Conflicts     []string `json:"conflicts,omitempty"`
+	Error         string   `json:"error,omitempty"`
+}
+
+type jsonSync struct {
+	jsonHeader
+	Dotfiles []jsonSyncDotfile `json:"dotfiles"`
+}
+
+type jsonDiffFile struct {
+	Path    string `json:"path"`
+	Change  string `json:"change"`
+	Binary  bool   `json:"binary"`
+	Added   int    `json:"added"`
+	Removed int    `json:"removed"`
+	Diff    string `json:"diff,omitempty"`
+}
+
+type jsonDiffDotfile struct {
+	Name  string         `json:"name"`
+	Files []jsonDiffFile `json:"files"`
+	Error string         `json:"error,omitempty"`
+}
+
+type jsonDiff struct {
+	jsonHeader
+	Dotfiles []jsonDiffDotfile `json:"dotfiles"`
+}
+
+type jsonStore struct {
+	Versioning  bool     `json:"versioning"`
+	Branch      string   `json:"branch,omitempty"`
+	Upstream    string   `json:"upstream,omitempty"`
+	Ahead       int      `json:"ahead"`
+	Behind      int      `json:"behind"`
+	Uncommitted []string `json:"uncommitted"`
+	Error       string   `json:"error,omitempty"`
+}
+
+type jsonStatusDotfile struct {
+	Name         string   `json:"name"`
+	LocalPath    string   `json:"local_path"`
+	StorePath    string   `json:"store_path"`
+	State        string   `json:"state"`
+	LocalChanges []string `json:"local_changes"`
+	StoreChanges []string `json:"store_changes"`
+	Conflicts    []string `json:"conflicts"`
+	Uncommitted  []string `json:"uncommitted"`
+	Error        string   `json:"error,omitempty"`
+}
+
+type jsonStatus struct {
+	jsonHeader
+	Store    jsonStore           `json:"store"`
+	Dotfiles []jsonStatusDotfile `json:"dotfiles"`
+}
+
+type jsonScan struct {
+	jsonHeader
+	Targets []string `json:"targets"`
+	FailOn  string   `json:"fail_on"`
+	Failed  bool     `json:"failed"`
+	jsonSecurity
+}
+
+// printJSON writes a JSON document to stdout
+func printJSON(document any) bool {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding JSON output: %v\n", err)
+		return false
+	}
+	return true
+}
+
+// RunListJSON prints the active dotfiles as JSON
+func RunListJSON(a *app.App) bool {
+	document := jsonList{
+		jsonHeader: jsonHeader{SchemaVersion: JSONSchemaVersion, Command: "list"},
+		Profile:    a.ActiveProfile,
+		Dotfiles:   []jsonListDotfile{},
+	}
+
+	for _, name := range sortedDotfileNames(a) {
+		path := a.Config.Dotfiles[name]
+		localPath := a.ExpandHomeDir(path)
+		mode := config.ModeCopy
+		if a.IsSymlinkMode(name) {
+			mode = config.ModeSymlink
+		}
+
+		document.Dotfiles = append(document.Dotfiles, jsonListDotfile{
+			Name:      name,
+			Path:      path,
+			LocalPath: localPath,
+			StorePath: a.StorePathFor(name, localPath),
+			Mode:      mode,
+			Template:  a.IsTemplate(name),
+			Encrypted: a.IsEncrypted(name),
+			Ignores:   nonNil(a.Config.DotfilesIgnores[name]),
+		})
+	}
+	return printJSON(document)
+}
+
+// RunSyncJSON syncs the given dotfiles, or every dotfile when names is empty, concurrently
+// and without prompting and prints the outcome as JSON. It returns false if a sync failed.
+func RunSyncJSON(a *app.App, names []string, skipSecurity bool, jobs int) bool {
+	if len(names) == 0 {
+		names = sortedDotfileNames(a)
+	}
+
+	opts := app.SyncOptions{
+		Reverse:      a.Config.Settings.ReverseSyncMode,
+		SkipSecurity: skipSecurity,
+		Policy:       a.PromptPolicy,
+	}
+	document := jsonSync{
+		jsonHeader: jsonHeader{SchemaVersion: JSONSchemaVersion, Command: "sync"},
+		Dotfiles:   []jsonSyncDotfile{},
+	}
+
+	ok := true
+	for _, report := range a.SyncBatch(names, opts, jobs, nil) {
+		if report.Status == app.SyncStatusFailed {
+			ok = false
+		}
+		document.Dotfiles = append(document.Dotfiles, syncReportJSON(report))
+	}
+	return printJSON(document) && ok
+}
+
+func syncReportJSON(report app.SyncReport) jsonSyncDotfile {
+	direction := "push"
+	if report.Reverse {
+		direction = "pull"
+	}
+
+	dotfile := jsonSyncDotfile{
+		Name:          report.Name,
+		LocalPath:     report.LocalPath,
+		StorePath:     report.StorePath,
+		Direction:     direction,
+		Status:        report.Status,
+		Reason:        report.Reason,
+		Changes:       []jsonChange{},
+		Commit:        report.Commit,
+		ExcludedFiles: report.SkippedFiles,
+	}
+	for _, change := range report.Changes {
+		dotfile.Changes = append(dotfile.Changes, jsonChange{Type: string(change.Type), Path: change.Path})
+	}
+	if report.Backup != nil {
+		dotfile.Backup = report.Backup.ID
+	}
+	if report.Security != nil {
+		dotfile.Security = securityJSON(report.Security)
+	}
+	for _, conflict := range report.Conflicts {
+		dotfile.Conflicts = append(dotfile.Conflicts, conflict.Path)
+	}
+	if report.Err != nil {
+		dotfile.Error = report.Err.Error()
+	}
+	return dotfile
+}
+
+// securityJSON converts a scan report, finding values are always redacted
+func securityJSON(report *security.ScanReport) *jsonSecurity {
+	result := &jsonSecurity{
+		ScannedFiles:  report.ScannedFiles,
+		SkippedFiles:  report.SkippedFiles,
+		TotalFindings: report.TotalFindings,
+		HighestRisk:   strings.ToLower(report.HighestRisk.String()),
+		Findings:      []jsonFinding{},
+
+		AcceptedFindings: report.AcceptedFindings,
+		AllowedFindings:  report.AllowedFindings,
+	}
+	for _, scan := range report.Results {
+		for _, finding := range scan.Findings {
+			file := finding.Location.FilePath
+			if file == "" {
+				file = scan.FilePath
+			}
+			result.Findings = append(result.Findings, jsonFinding{
+				File:       file,
+				Line:       finding.Location.LineNumber,
+				Type:       string(finding.Type),
+				Risk:       strings.ToLower(finding.RiskLevel.String()),
+				Confidence: finding.Confidence,
+				Value:      redactedValue(finding),
+				Reasons:    finding.Reasons,
+			})
+		}
+	}
+	return result
+}
+
+// RunDiffJSON prints the pending changes of the given dotfiles, or of every dotfile when
+// names is empty, as JSON. It returns false if a dotfile could not be compared.
+func RunDiffJSON(a *app.App, names []string, stat bool) bool {
+	if len(names) == 0 {
+		names = sortedDotfileNames(a)
+	}
+
+	document := jsonDiff{
+		jsonHeader: jsonHeader{SchemaVersion: JSONSchemaVersion, Command: "diff"},
+		Dotfiles:   []jsonDiffDotfile{},
+	}
+
+	ok := true
+	for _, name := range names {
+		dotfile := jsonDiffDotfile{Name: name, Files: []jsonDiffFile{}}
+
+		path, found := a.Config.Dotfiles[name]
+		if !found {
+			dotfile.Error = fmt.Sprintf("dotfile '%s' not found", name)
+			document.Dotfiles = append(document.Dotfiles, dotfile)
+			ok = false
+			continue
+		}
+
+		diffs, err := dotfileDiffs(a, name, path)
+		if err != nil {
+			dotfile.Error = err.Error()
+			ok = false
+		}
+		for _, diff := range diffs {
+			file := jsonDiffFile{
+				Path:    diff.Path(),
+				Change:  string(diff.Change),
+				Binary:  diff.Binary,
+				Added:   diff.Added,
+				Removed: diff.Removed,
+			}
+			if !stat {
+				file.Diff = diff.Unified
+			}
+			dotfile.Files = append(dotfile.Files, file)
+		}
+		document.Dotfiles = append(document.Dotfiles, dotfile)
+	}
+	return printJSON(document) && ok
+}
+
+// redactedValue never returns the raw secret
+func redactedValue(finding security.Finding) string {
+	if finding.RawValue != "" {
+		return finding.Redact()
+	}
+	return finding.Value
+}
+
+// RunStatusJSON prints the drift of the given dotfiles, or of every dotfile when names is
+// empty, and the state of the store repository as JSON
+func RunStatusJSON(a *app.App, names []string, fetch bool) bool {
+	if len(names) == 0 {
+		names = sortedDotfileNames(a)
+	}
+
+	ok := true
+	store, err := a.StoreStatus(fetch)
+	document := jsonStatus{
+		jsonHeader: jsonHeader{SchemaVersion: JSONSchemaVersion, Command: "status"},
+		Store:      jsonStore{Versioning: store.Versioning, Uncommitted: nonNil(store.Uncommitted)},
+		Dotfiles:   []jsonStatusDotfile{},
+	}
+	if err != nil {
+		document.Store.Error = err.Error()
+		ok = false
+	}
+	if store.Branch != nil {
+		document.Store.Branch = store.Branch.Branch
+		document.Store.Upstream = store.Branch.Upstream
+		document.Store.Ahead = store.Branch.Ahead
+		document.Store.Behind = store.Branch.Behind
+	}
+
+	for _, name := range names {
+		status := a.DotfileStatus(name, store)
+		dotfile := jsonStatusDotfile{
+			Name:         status.Name,
+			LocalPath:    status.LocalPath,
+			StorePath:    status.StorePath,
+			State:        status.State,
+			LocalChanges: nonNil(status.LocalChanges),
+			StoreChanges: nonNil(status.StoreChanges),
+			Conflicts:    nonNil(status.Conflicts),
+			Uncommitted:  nonNil(status.Uncommitted),
+		}
+		if status.Err != nil {
+			dotfile.Error = status.Err.Error()
+			ok = false
+		}
+		document.Dotfiles = append(document.Dotfiles, dotfile)
+	}
+	return printJSON(document) && ok
+}
+
+// nonNil keeps empty lists as [] rather than null in JSON documents
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+func sortedDotfileNames(a *app.App) []string {
+	names := make([]string, 0, len(a.Config.Dotfiles))
+	for name := range a.Config.Dotfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/bnema/gart/internal/app"
 	"github.com/bnema/gart/internal/config"
+	"github.com/bnema/gart/internal/system"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
@@ -26,6 +27,7 @@ type ListModel struct {
 	Dotfiles      map[string]string
 	Footer        string
 	ConfirmRemove bool
+	DryRun        bool
 }
 
 type KeyMap struct {
@@ -51,14 +53,28 @@ func DefaultKeyMap() KeyMap {
 	}
 }
 
-func RunListView(app *app.App) {
+func RunListView(app *app.App, dryRun bool) {
 	dotfiles := app.GetDotfiles()
 	if len(dotfiles) == 0 {
 		fmt.Println("No dotfiles found. Please add some dotfiles first.")
 		return
 	}
 
+	// Without prompts there is no table to browse, print the dotfiles instead
+	if app.NonInteractive() {
+		names := make([]string, 0, len(dotfiles))
+		for name := range dotfiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("%s\t%s\n", name, dotfiles[name])
+		}
+		return
+	}
+
 	model := InitListModel(*app.Config, app)
+	model.DryRun = dryRun
 	p := tea.NewProgram(model)
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Error running program: %v\n", err)
@@ -161,6 +177,12 @@ func (m ListModel) removeSelectedEntry() (tea.Model, tea.Cmd) {
 		name := selectedRow[0]
 		path := selectedRow[1]
 
+		if m.DryRun {
+			m.ConfirmRemove = false
+			m.Footer = m.removalPlanFooter(name, path)
+			return m, nil
+		}
+
 		// Remove the selected entry from the config
 		err := m.App.RemoveDotFile(path, name)
 		if err != nil {
@@ -190,6 +212,24 @@ func (m ListModel) removeSelectedEntry() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// removalPlanFooter describes what removing a dotfile would do, for dry-run mode
+func (m ListModel) removalPlanFooter(name, path string) string {
+	storePath := m.App.StorePathFor(name, path)
+	plan, err := system.PlanRemoval(storePath)
+	if err != nil {
+		return errorStyle.Render(fmt.Sprintf("Error planning removal: %s", err))
+	}
+
+	footer := fmt.Sprintf("[dry-run] would remove '%s' from %s", name, m.App.GetConfigFilePath())
+	for _, change := range plan.Changes {
+		footer += fmt.Sprintf("\n[dry-run] would %s %s (%s)", change.Type, change.Target, formatSize(change.Size))
+	}
+	if m.App.Config.Settings.GitVersioning {
+		footer += fmt.Sprintf("\n[dry-run] would commit: Remove %s", name)
+	}
+	return alertStyle.Render(footer)
+}
+
 func clearFooterAfter(d time.Duration) tea.Cmd {
 	return tea.Tick(d, func(t time.Time) tea.Msg {
 		return defaultFooterMsg{}
@@ -0,0 +1,179 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bnema/gart/internal/app"
+	"github.com/bnema/gart/internal/git"
+	"github.com/bnema/gart/internal/system"
+)
+
+// RunMergeFromView shows the dotfiles that differ on another host's branch and adopts the
+// selected dotfiles or files into the current branch and the local system. With all, every
+// differing dotfile is adopted without prompting; with dryRun, nothing is adopted.
+// It returns false if the comparison or an adoption failed.
+func RunMergeFromView(a *app.App, host string, names []string, all, dryRun bool) bool {
+	ref, err := a.HostBranch(host)
+	if err != nil {
+		fmt.Printf("%s\n", errorStyle.Render(fmt.Sprintf("Error reading the branch of '%s': %v", host, err)))
+		return false
+	}
+
+	diffs, err := a.DiffHost(ref, names)
+	if err != nil {
+		fmt.Printf("%s\n", errorStyle.Render(fmt.Sprintf("Error comparing with '%s': %v", host, err)))
+		return false
+	}
+	if len(diffs) == 0 {
+		fmt.Println(unchangedStyle.Render(fmt.Sprintf("No dotfile differs from '%s'.", host)))
+		return true
+	}
+
+	// In non-interactive mode the prompt policy adopts everything or nothing
+	skip := false
+	if !all && !dryRun && a.NonInteractive() {
+		switch a.PromptPolicy {
+		case system.PromptProceed:
+			all = true
+		case system.PromptSkip:
+			skip = true
+		default:
+			fmt.Printf("%s\n", errorStyle.Render(fmt.Sprintf(
+				"Choosing the dotfiles to adopt from '%s': %v, pass --all or --dry-run", host, system.ErrInputRequired)))
+			return false
+		}
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	var adoptions []app.Adoption
+	for _, diff := range diffs {
+		displayHostDiff(host, diff)
+		if dryRun || skip {
+			continue
+		}
+		if all {
+			adoptions = append(adoptions, app.Adoption{Name: diff.Name})
+			continue
+		}
+
+		adoption, quit, err := promptAdoption(reader, diff)
+		if err != nil {
+			fmt.Printf("%s\n", errorStyle.Render(fmt.Sprintf("Error reading choice: %v", err)))
+			return false
+		}
+		if adoption != nil {
+			adoptions = append(adoptions, *adoption)
+		}
+		if quit {
+			break
+		}
+	}
+
+	if dryRun {
+		return true
+	}
+	if len(adoptions) == 0 {
+		fmt.Println("Nothing adopted.")
+		return true
+	}
+
+	results, err := a.AdoptFromHost(host, ref, adoptions)
+	if err != nil {
+		fmt.Printf("%s\n", errorStyle.Render(fmt.Sprintf("Error adopting from '%s': %v", host, err)))
+		return false
+	}
+
+	ok := true
+	for _, result := range results {
+		fmt.Print(changedStyle.Render(fmt.Sprintf("Deploying '%s'...", result.Name)))
+		if result.Err != nil {
+			fmt.Printf(" %s\n", errorStyle.Render(fmt.Sprintf("Error: %v", result.Err)))
+			ok = false
+			continue
+		}
+		fmt.Printf(" %s\n", successStyle.Render(fmt.Sprintf("Success! (%s)", result.LocalPath)))
+	}
+	return ok
+}
+
+// displayHostDiff lists the files of a dotfile that differ on a host's branch
+func displayHostDiff(host string, diff app.HostDiff) {
+	fmt.Printf("%s\n", alertStyle.Render(fmt.Sprintf("'%s' differs on '%s':", diff.Name, host)))
+	for _, file := range diff.Files {
+		fmt.Printf("  %s\n", hostChangeLabel(file))
+	}
+}
+
+// hostChangeLabel describes what adopting a file from the host branch would do
+func hostChangeLabel(file git.FileChange) string {
+	switch {
+	case file.From == nil:
+		return successStyle.Render("+ " + file.Path)
+	case file.To == nil:
+		return errorStyle.Render("- " + file.Path)
+	default:
+		return changedStyle.Render("~ " + file.Path)
+	}
+}
+
+// promptAdoption asks what to adopt from a differing dotfile. It returns nil when the dotfile
+// is skipped, and quit when no further dotfile should be offered.
+func promptAdoption(reader *bufio.Reader, diff app.HostDiff) (*app.Adoption, bool, error) {
+	for {
+		fmt.Printf("Adopt '%s'? [%s]ll files, [%s]ile by file, show [%s]iff, [%s]kip, [%s]uit: ",
+			diff.Name, boldStyle.Render("a"), boldStyle.Render("f"), boldStyle.Render("d"),
+			boldStyle.Render("s"), boldStyle.Render("q"))
+
+		input, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, false, err
+		}
+
+		switch strings.ToLower(strings.TrimSpace(input)) {
+		case "a", "all":
+			return &app.Adoption{Name: diff.Name}, false, nil
+		case "f", "file":
+			files, err := promptAdoptionFiles(reader, diff.Files)
+			if err != nil || len(files) == 0 {
+				return nil, false, err
+			}
+			return &app.Adoption{Name: diff.Name, Files: files}, false, nil
+		case "d", "diff":
+			DisplayDiffs(fileChangeDiffs(diff.Files))
+		case "s", "skip", "":
+			return nil, false, nil
+		case "q", "quit":
+			return nil, true, nil
+		default:
+			fmt.Println("Invalid choice, please try again.")
+		}
+	}
+}
+
+// promptAdoptionFiles asks for each differing file whether to adopt it
+func promptAdoptionFiles(reader *bufio.Reader, files []git.FileChange) ([]string, error) {
+	var selected []string
+	for _, file := range files {
+		for {
+			fmt.Printf("  %s [y/N/d]: ", hostChangeLabel(file))
+			input, err := reader.ReadString('\n')
+			if err != nil {
+				return nil, err
+			}
+
+			answer := strings.ToLower(strings.TrimSpace(input))
+			if answer == "d" {
+				DisplayDiffs(fileChangeDiffs([]git.FileChange{file}))
+				continue
+			}
+			if answer == "y" || answer == "yes" {
+				selected = append(selected, file.Path)
+			}
+			break
+		}
+	}
+	return selected, nil
+}
@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/bnema/gart/internal/app"
+	"github.com/bnema/gart/internal/system"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DisplayPlan prints the file operations of a plan, one per line
+func DisplayPlan(plan *system.Plan) {
+	if plan.IsEmpty() {
+		fmt.Println(unchangedStyle.Render("  No file operations."))
+		return
+	}
+
+	for _, change := range plan.Changes {
+		target := change.Target
+		if change.IsDir {
+			target += string(filepath.Separator)
+		}
+		fmt.Printf("  %s %s %s\n",
+			getChangeStyle(change.Type).Render(fmt.Sprintf("%-11s", change.Type)),
+			target,
+			unchangedStyle.Render(fmt.Sprintf("(%s)", formatSize(change.Size))),
+		)
+	}
+
+	fmt.Println(unchangedStyle.Render(fmt.Sprintf("  %d create, %d modify, %d delete, %d type-change, %s total",
+		plan.Count(system.ChangeCreate),
+		plan.Count(system.ChangeModify),
+		plan.Count(system.ChangeDelete),
+		plan.Count(system.ChangeTypeChange),
+		formatSize(plan.TotalSize()),
+	)))
+}
+
+// RunSyncPlanView prints what a sync of the current dotfile would do without changing anything
+func RunSyncPlanView(app *app.App, ignores []string) bool {
+	name := app.Dotfile.Name
+	localPath := app.Dotfile.Path
+	storePath := app.StorePathFor(name, localPath)
+
+	fmt.Println(boldStyle.Render(fmt.Sprintf("[dry-run] sync '%s'", name)))
+
+	if app.IsSymlinkMode(name) {
+		fmt.Printf("  symlinked to %s, would only commit the store entry\n", storePath)
+		return true
+	}
+
+	reverse := app.Config.Settings.ReverseSyncMode
+
+	// Files changed on the destination side only since the last sync are taken from it first
+	taken, cleanupTaken, err := app.PlanOneSided(name, localPath, storePath, ignores, reverse)
+	if err != nil {
+		fmt.Printf("%s\n", errorStyle.Render(err.Error()))
+		return false
+	}
+	defer cleanupTaken()
+
+	localSide, storeSide, cleanup, err := app.SyncSides(name, localPath, storePath, reverse)
+	if err != nil {
+		fmt.Printf("%s\n", errorStyle.Render(fmt.Sprintf("Template error: %v", err)))
+		return false
+	}
+	defer cleanup()
+
+	plan, err := system.PlanSync(localSide, storeSide, ignores, reverse)
+	if err != nil {
+		fmt.Printf("%s\n", errorStyle.Render(fmt.Sprintf("Error comparing dotfiles: %v", err)))
+		return false
+	}
+
+	if !taken.IsEmpty() {
+		var targets []string
+		for _, change := range taken.Changes {
+			targets = append(targets, change.Target)
+		}
+		plan = plan.Excluding(targets)
+
+		from := "store"
+		if reverse {
+			from = "local"
+		}
+		fmt.Printf("  changed in the %s only since the last sync:\n", from)
+		DisplayPlan(taken)
+	}
+
+	DisplayPlan(plan)
+	storeChanged := (!plan.IsEmpty() && !reverse) || (!taken.IsEmpty() && reverse)
+	if storeChanged && app.Config.Settings.GitVersioning {
+		fmt.Println(unchangedStyle.Render("  would commit: Update " + name))
+	}
+	return true
+}
+
+// RunAddPlanView prints what adding a dotfile would do without changing anything
+func RunAddPlanView(app *app.App, path string, dotfileName string, ignores []string, symlink bool) {
+	cleanedPath := filepath.Clean(app.ExpandHomeDir(path))
+
+	fmt.Println(boldStyle.Render(fmt.Sprintf("[dry-run] add '%s'", dotfileName)))
+
+	var storePath string
+	if app.IsDir(cleanedPath) {
+		storePath = filepath.Join(app.StoragePath, dotfileName)
+	} else {
+		storePath = filepath.Join(app.StoragePath, filepath.Base(cleanedPath))
+	}
+
+	if symlink {
+		storePath = app.StorePathFor(dotfileName, cleanedPath)
+		fmt.Printf("  %s %s -> %s\n", getChangeStyle(system.ChangeCreate).Render(fmt.Sprintf("%-11s", "move")), cleanedPath, storePath)
+		fmt.Printf("  %s %s -> %s\n", getChangeStyle(system.ChangeCreate).Render(fmt.Sprintf("%-11s", "symlink")), cleanedPath, storePath)
+	} else {
+		plan, err := system.PlanChanges(cleanedPath, storePath, ignores)
+		if err != nil {
+			fmt.Printf("%s\n", errorStyle.Render(fmt.Sprintf("Error planning add: %v", err)))
+			return
+		}
+		DisplayPlan(plan)
+	}
+
+	fmt.Println(unchangedStyle.Render(fmt.Sprintf("  would record %s = %s in %s", dotfileName, cleanedPath, app.ConfigFilePath)))
+	if app.Config.Settings.GitVersioning {
+		fmt.Println(unchangedStyle.Render("  would commit: Add " + dotfileName))
+	}
+}
+
+func getChangeStyle(changeType system.ChangeType) lipgloss.Style {
+	switch changeType {
+	case system.ChangeCreate:
+		return successStyle
+	case system.ChangeModify:
+		return changedStyle
+	case system.ChangeDelete:
+		return errorStyle
+	case system.ChangeTypeChange:
+		return alertStyle
+	default:
+		return unchangedStyle
+	}
+}
+
+// formatSize renders a byte count in a human readable form
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
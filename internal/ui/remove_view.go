@@ -0,0 +1,34 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/bnema/gart/internal/app"
+)
+
+// RunRemoveView removes a dotfile from gart once confirmed, the local files are kept.
+// It returns false if the dotfile is unknown or could not be removed.
+func RunRemoveView(app *app.App, name string) bool {
+	path, ok := app.Config.Dotfiles[name]
+	if !ok {
+		fmt.Printf("%s\n", errorStyle.Render(fmt.Sprintf("Dotfile '%s' not found.", name)))
+		return false
+	}
+
+	confirmed, err := app.Confirm(fmt.Sprintf("Are you sure you want to remove '%s' (%s)?", name, path))
+	if err != nil {
+		fmt.Printf("%s\n", errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		return false
+	}
+	if !confirmed {
+		fmt.Println(unchangedStyle.Render("Removal cancelled."))
+		return true
+	}
+
+	if err := app.RemoveDotFile(path, name); err != nil {
+		fmt.Printf("%s\n", errorStyle.Render(fmt.Sprintf("Error removing dotfile: %v", err)))
+		return false
+	}
+	fmt.Println(successStyle.Render(fmt.Sprintf("Dotfile '%s' removed successfully", name)))
+	return true
+}
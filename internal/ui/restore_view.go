@@ -0,0 +1,34 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bnema/gart/internal/app"
+)
+
+// RunRestoreView rolls a dotfile back to an earlier version of the store and prints the result.
+// It returns false if the rollback or the deploy failed.
+func RunRestoreView(app *app.App, name, rev string, at time.Time, deploy bool) bool {
+	result, err := app.RestoreDotfile(name, rev, at, deploy)
+	if err != nil {
+		fmt.Printf("%s\n", errorStyle.Render(fmt.Sprintf("Error restoring '%s': %v", name, err)))
+		return false
+	}
+
+	fmt.Printf("%s\n", successStyle.Render(fmt.Sprintf("Restored '%s' from %s (%s, %s)",
+		name, result.Commit.ShortHash(), result.Commit.Date.Local().Format(historyDateFormat), result.Commit.Subject())))
+
+	if result.Deploy == nil {
+		fmt.Println(unchangedStyle.Render(fmt.Sprintf("The local files are unchanged, run 'gart deploy %s' to apply it.", name)))
+		return true
+	}
+
+	fmt.Print(changedStyle.Render(fmt.Sprintf("Deploying '%s'...", name)))
+	if result.Deploy.Err != nil {
+		fmt.Printf(" %s\n", errorStyle.Render(fmt.Sprintf("Error: %v", result.Deploy.Err)))
+		return false
+	}
+	fmt.Printf(" %s\n", successStyle.Render(fmt.Sprintf("Success! (%s)", result.Deploy.LocalPath)))
+	return true
+}
@@ -0,0 +1,222 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/bnema/gart/internal/app"
+	"github.com/bnema/gart/internal/security"
+	"github.com/bnema/gart/internal/version"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// RunScan scans the given dotfiles and paths, or every dotfile when args is empty, and the
+// store when store is set. The report is printed in format (text, json or sarif), or
+// written to reportPath with the text report printed instead. It returns false when the
+// highest risk found reaches failOn, RiskLevelNone never fails.
+func RunScan(a *app.App, args []string, store bool, format, reportPath string, failOn security.RiskLevel) (bool, error) {
+	targets, err := a.ScanTargets(args, store)
+	if err != nil {
+		return false, err
+	}
+	report, err := a.Scan(targets)
+	if err != nil {
+		return false, err
+	}
+	passed := failOn == security.RiskLevelNone || report.HighestRisk < failOn
+
+	if reportPath == "" {
+		return passed, writeScanReport(os.Stdout, targets, report, format, failOn, passed, true)
+	}
+
+	file, err := os.Create(reportPath)
+	if err != nil {
+		return false, fmt.Errorf("error creating report: %w", err)
+	}
+	if err := writeScanReport(file, targets, report, format, failOn, passed, false); err != nil {
+		file.Close()
+		return false, err
+	}
+	if err := file.Close(); err != nil {
+		return false, fmt.Errorf("error writing report: %w", err)
+	}
+
+	// Keep stdout free for machine readable output
+	if !a.JSONOutput {
+		writeScanText(os.Stdout, report, failOn, passed, true)
+		fmt.Printf("Report written to %s\n", reportPath)
+	}
+	return passed, nil
+}
+
+// RunUpdateBaseline scans like RunScan and accepts every finding in the baseline, so that
+// later scans and syncs only report new findings. It returns false on errors.
+func RunUpdateBaseline(a *app.App, args []string, store bool) bool {
+	targets, err := a.ScanTargets(args, store)
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		return false
+	}
+	report, err := a.Scan(targets)
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		return false
+	}
+
+	// Only the findings not accepted yet are listed
+	writeScanText(os.Stdout, report, security.RiskLevelNone, true, true)
+
+	accepted, err := a.UpdateBaseline(report)
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error updating the baseline: %v", err)))
+		return false
+	}
+	fmt.Println(successStyle.Render(fmt.Sprintf("%d finding(s) accepted in %s", accepted, a.BaselinePath())))
+	return true
+}
+
+// RunTestRule runs a user-defined rule alone on the given dotfiles and paths, every
+// dotfile when args is empty, and the store when store is set. "-" reads from stdin. Every
+// match is printed, with the reason when the rule leaves it out. It returns false when no
+// match would be reported.
+func RunTestRule(a *app.App, id string, args []string, store bool) (bool, error) {
+	var paths []string
+	readStdin := false
+	for _, arg := range args {
+		if arg == "-" {
+			readStdin = true
+		} else {
+			paths = append(paths, arg)
+		}
+	}
+
+	var matches []security.RuleMatch
+	if readStdin {
+		content, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return false, fmt.Errorf("error reading stdin: %w", err)
+		}
+		if matches, err = a.TestRuleContent(id, content); err != nil {
+			return false, err
+		}
+	}
+	if !readStdin || len(paths) > 0 || store {
+		targets, err := a.ScanTargets(paths, store)
+		if err != nil {
+			return false, err
+		}
+		found, err := a.TestRule(id, targets)
+		if err != nil {
+			return false, err
+		}
+		matches = append(matches, found...)
+	}
+
+	reported := 0
+	for _, match := range matches {
+		finding := match.Finding
+		location := fmt.Sprintf("%s:%d", finding.Location.FilePath, finding.Location.LineNumber)
+		if match.Skipped != "" {
+			fmt.Printf("%s: %s %s\n", location, unchangedStyle.Render(finding.Value), unchangedStyle.Render("skipped, "+match.Skipped))
+			continue
+		}
+		reported++
+		fmt.Printf("%s: %s %s %s\n", location, getRiskStyle(finding.RiskLevel).Render(finding.RiskLevel.String()), finding.Type, finding.Value)
+	}
+
+	summary := fmt.Sprintf("Rule %s: %d match(es), %d reported", id, len(matches), reported)
+	if reported == 0 {
+		fmt.Println(alertStyle.Render(summary))
+		return false, nil
+	}
+	fmt.Println(successStyle.Render(summary))
+	return true, nil
+}
+
+func writeScanReport(w io.Writer, targets []app.ScanTarget, report *security.ScanReport, format string, failOn security.RiskLevel, passed, styled bool) error {
+	switch format {
+	case "json":
+		document := jsonScan{
+			jsonHeader:   jsonHeader{SchemaVersion: JSONSchemaVersion, Command: "scan"},
+			Targets:      []string{},
+			FailOn:       strings.ToLower(failOn.String()),
+			Failed:       !passed,
+			jsonSecurity: *securityJSON(report),
+		}
+		for _, target := range targets {
+			if target.Name != "" {
+				document.Targets = append(document.Targets, target.Name)
+			} else {
+				document.Targets = append(document.Targets, target.Path)
+			}
+		}
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(document); err != nil {
+			return fmt.Errorf("error encoding JSON report: %w", err)
+		}
+		return nil
+	case "sarif":
+		baseDir, err := os.Getwd()
+		if err != nil {
+			baseDir = ""
+		}
+		if err := report.WriteSARIF(w, version.Version, baseDir); err != nil {
+			return fmt.Errorf("error encoding SARIF report: %w", err)
+		}
+		return nil
+	default:
+		writeScanText(w, report, failOn, passed, styled)
+		return nil
+	}
+}
+
+// writeScanText prints one line per finding followed by a summary, styles are only used
+// on the terminal
+func writeScanText(w io.Writer, report *security.ScanReport, failOn security.RiskLevel, passed, styled bool) {
+	render := func(style lipgloss.Style, text string) string {
+		if styled {
+			return style.Render(text)
+		}
+		return text
+	}
+
+	for _, result := range report.Results {
+		for _, finding := range result.Findings {
+			location := finding.Location.FilePath
+			if location == "" {
+				location = result.FilePath
+			}
+			if finding.Location.LineNumber > 0 {
+				location = fmt.Sprintf("%s:%d", location, finding.Location.LineNumber)
+			}
+
+			line := fmt.Sprintf("%s: %s %s", location, render(getRiskStyle(finding.RiskLevel), finding.RiskLevel.String()), finding.Type)
+			if value := redactedValue(finding); value != "" {
+				line += " " + value
+			}
+			fmt.Fprintf(w, "%s (confidence: %.0f%%)\n", line, finding.Confidence*100)
+		}
+	}
+	if report.TotalFindings > 0 {
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintln(w, report.Summary())
+	if report.AcceptedFindings > 0 {
+		fmt.Fprintln(w, render(unchangedStyle, fmt.Sprintf("%d finding(s) accepted in the baseline", report.AcceptedFindings)))
+	}
+	if report.AllowedFindings > 0 {
+		fmt.Fprintln(w, render(unchangedStyle, fmt.Sprintf("%d finding(s) silenced by gart:allow comments", report.AllowedFindings)))
+	}
+	switch {
+	case !passed:
+		fmt.Fprintln(w, render(errorStyle, fmt.Sprintf("Highest risk %s reaches --fail-on %s", report.HighestRisk, strings.ToLower(failOn.String()))))
+	case report.TotalFindings == 0:
+		fmt.Fprintln(w, render(securityPassStyle, "No security issues found."))
+	}
+}
@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/bnema/gart/internal/app"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// RunStatusView prints the state of the store repository and how far the given dotfiles,
+// or every dotfile when names is empty, drifted since their last sync
+func RunStatusView(a *app.App, names []string, fetch bool) bool {
+	if len(names) == 0 {
+		names = sortedDotfileNames(a)
+	}
+
+	ok := true
+	store, err := a.StoreStatus(fetch)
+	if err != nil {
+		fmt.Printf("%s\n", errorStyle.Render(fmt.Sprintf("Error reading the store repository: %v", err)))
+		ok = false
+	} else {
+		displayStoreStatus(store)
+	}
+
+	width := 0
+	for _, name := range names {
+		width = max(width, len(name))
+	}
+
+	for _, name := range names {
+		status := a.DotfileStatus(name, store)
+		if status.Err != nil {
+			ok = false
+		}
+
+		description, style := driftDescription(status)
+		fmt.Printf("  %-*s  %s\n", width, name, style.Render(description))
+		displayStatusFiles("local", status.LocalChanges)
+		displayStatusFiles("store", status.StoreChanges)
+		displayStatusFiles("uncommitted", status.Uncommitted)
+	}
+	return ok
+}
+
+// displayStoreStatus prints the branch of the store and what is left to commit, push or pull
+func displayStoreStatus(store app.StoreStatus) {
+	if !store.Versioning {
+		fmt.Println(unchangedStyle.Render("Store: git versioning disabled"))
+		return
+	}
+
+	summary := "Store"
+	if store.Branch != nil {
+		summary = fmt.Sprintf("Store on branch %s", store.Branch.Branch)
+	}
+	fmt.Println(boldStyle.Render(summary))
+
+	if len(store.Uncommitted) > 0 {
+		fmt.Println(alertStyle.Render(fmt.Sprintf("  %d uncommitted file(s)", len(store.Uncommitted))))
+	}
+	switch {
+	case store.Branch == nil:
+		fmt.Println(unchangedStyle.Render("  No remote configured"))
+	case store.Branch.Upstream == "":
+		fmt.Println(alertStyle.Render(fmt.Sprintf("  Never pushed, %d unpushed commit(s)", store.Branch.Ahead)))
+	case store.Branch.Ahead == 0 && store.Branch.Behind == 0:
+		fmt.Println(successStyle.Render(fmt.Sprintf("  Up to date with %s", store.Branch.Upstream)))
+	default:
+		fmt.Println(alertStyle.Render(fmt.Sprintf("  %d commit(s) to push, %d to pull from %s (as of the last fetch)",
+			store.Branch.Ahead, store.Branch.Behind, store.Branch.Upstream)))
+	}
+	fmt.Println()
+}
+
+func driftDescription(status app.DotfileStatus) (string, lipgloss.Style) {
+	switch status.State {
+	case app.DriftInSync:
+		return "in sync", successStyle
+	case app.DriftLocalModified:
+		return "modified locally", alertStyle
+	case app.DriftStoreModified:
+		return "modified in the store", alertStyle
+	case app.DriftBothModified:
+		if len(status.Conflicts) > 0 {
+			return fmt.Sprintf("modified on both sides, %d conflict(s)", len(status.Conflicts)), errorStyle
+		}
+		return "modified on both sides", alertStyle
+	case app.DriftModified:
+		return "differs from the store", alertStyle
+	case app.DriftMissingLocal:
+		return "missing locally", errorStyle
+	case app.DriftMissingStore:
+		return "missing in the store", errorStyle
+	case app.DriftNotLinked:
+		return "not linked to the store", errorStyle
+	case app.DriftUnknown:
+		return "unknown, sync it once to record its state", unchangedStyle
+	default:
+		return fmt.Sprintf("error: %v", status.Err), errorStyle
+	}
+}
+
+func displayStatusFiles(side string, files []string) {
+	for _, file := range files {
+		fmt.Printf("      %s %s\n", unchangedStyle.Render(side+":"), file)
+	}
+}
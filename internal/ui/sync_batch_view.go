@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/bnema/gart/internal/app"
+)
+
+// RunSyncBatchView syncs every dotfile with up to jobs concurrent workers and prints each
+// outcome as it completes. Security and conflict prompts are asked one at a time, and the
+// changes are committed once for the whole batch. It returns false if a sync failed or
+// was aborted.
+func RunSyncBatchView(a *app.App, skipSecurity bool, jobs int) bool {
+	names := sortedDotfileNames(a)
+	if jobs <= 0 {
+		jobs = a.SyncJobs()
+	}
+	fmt.Println(unchangedStyle.Render(fmt.Sprintf("Syncing %d dotfile(s) with %d worker(s)...", len(names), min(jobs, len(names)))))
+
+	skipAllSecurity := false
+	opts := syncOptions(a, skipSecurity, &skipAllSecurity)
+
+	ok := true
+	reports := a.SyncBatch(names, opts, jobs, func(report app.SyncReport) {
+		displaySyncReport(report)
+	})
+
+	var committed []string
+	commit := ""
+	for _, report := range reports {
+		if report.Status == app.SyncStatusFailed {
+			ok = false
+		}
+		if report.Commit != "" {
+			committed = append(committed, report.Name)
+			commit = report.Commit
+		}
+	}
+	if len(committed) > 0 {
+		fmt.Println(successStyle.Render(fmt.Sprintf("Committed %d dotfile(s) as %s.", len(committed), commit[:min(7, len(commit))])))
+	}
+	return ok
+}
+
+// displaySyncReport prints the outcome of the sync of a dotfile
+func displaySyncReport(report app.SyncReport) {
+	switch report.Status {
+	case app.SyncStatusFailed:
+		fmt.Printf("%s\n", errorStyle.Render(fmt.Sprintf("Error syncing '%s': %v", report.Name, report.Err)))
+	case app.SyncStatusSkipped:
+		if report.Reason == app.ErrSyncAborted.Error() {
+			fmt.Println(unchangedStyle.Render(fmt.Sprintf("Sync of '%s' aborted.", report.Name)))
+			return
+		}
+		fmt.Println(alertStyle.Render(fmt.Sprintf("Skipping '%s': %s.", report.Name, report.Reason)))
+	case app.SyncStatusUnchanged:
+		location := "in store"
+		if report.Reverse {
+			location = "in local config"
+		}
+		fmt.Println(unchangedStyle.Render(fmt.Sprintf("No changes detected %s for '%s'.", location, report.Name)))
+	default:
+		// Symlinked dotfiles have nothing to copy, only the store is committed
+		if !report.Changed() {
+			fmt.Println(unchangedStyle.Render(fmt.Sprintf("Symlinked '%s' checked, store changes committed.", report.Name)))
+			return
+		}
+		if report.Backup != nil {
+			fmt.Println(unchangedStyle.Render(fmt.Sprintf("Backed up %d local path(s) to backup '%s'", len(report.Backup.Paths), report.Backup.ID)))
+		}
+		direction := "Updating store"
+		if report.Reverse {
+			direction = "Updating local config"
+		}
+		fmt.Printf("%s %s\n",
+			changedStyle.Render(fmt.Sprintf("Changes detected in '%s'. %s...", report.Name, direction)),
+			successStyle.Render("Success!"))
+		displaySkippedFiles(report.SkippedFiles)
+	}
+}
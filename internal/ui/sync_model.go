@@ -2,152 +2,80 @@ package ui
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
 
 	"github.com/bnema/gart/internal/app"
 	"github.com/bnema/gart/internal/security"
-	"github.com/bnema/gart/internal/system"
 )
 
-// RunSyncView runs the sync view and returns whether to continue with additional syncs
+// RunSyncView syncs a dotfile, asking what to do with security findings and conflicts like
+// RunSyncBatchView, and returns whether to continue with additional syncs.
 // skipAllSecurity pointer allows the function to set the flag if user chooses "skip all"
-func RunSyncView(app *app.App, ignores []string, skipSecurity bool, skipAllSecurity *bool) bool {
-	sourcePath := app.Dotfile.Path
-
-	// Check if the source is a file or directory
-	sourceInfo, err := os.Stat(sourcePath)
-	if err != nil {
-		fmt.Printf("Error accessing source path: %v\n", err)
-		return false
-	}
-
-	var storePath string
-	if sourceInfo.IsDir() {
-		// For directories, use the dotfile name as is
-		storePath = filepath.Join(app.StoragePath, app.Dotfile.Name)
-	} else {
-		// For files, include the file extension in the store path
-		ext := filepath.Ext(sourcePath)
-		storePath = filepath.Join(app.StoragePath, app.Dotfile.Name+ext)
+func RunSyncView(a *app.App, name string, skipSecurity bool, skipAllSecurity *bool) bool {
+	if skipAllSecurity == nil {
+		skipAllSecurity = new(bool)
 	}
+	opts := syncOptions(a, skipSecurity || *skipAllSecurity, skipAllSecurity)
 
-	// Check skip all flag
-	if skipAllSecurity != nil && *skipAllSecurity {
-		skipSecurity = true
+	report := a.SyncUnattended(name, opts)
+	if report.Security != nil && report.Security.TotalFindings == 0 && report.Status != app.SyncStatusFailed {
+		fmt.Printf("%s\n", securityPassStyle.Render(fmt.Sprintf("󰸞 Security scan of '%s' passed - no issues found.", name)))
 	}
+	displaySyncReport(report)
+	return report.Status != app.SyncStatusFailed
+}
+
+// syncOptions returns the options of an interactive sync: security findings and conflicts
+// are listed and asked about, or answered by the prompt policy. "Skip all" sets
+// skipAllSecurity and bypasses the security prompts of the remaining dotfiles.
+func syncOptions(a *app.App, skipSecurity bool, skipAllSecurity *bool) app.SyncOptions {
+	return app.SyncOptions{
+		Reverse:      a.Config.Settings.ReverseSyncMode,
+		SkipSecurity: skipSecurity,
+		Policy:       a.PromptPolicy,
+		DecideSecurity: func(report *app.SyncReport, securityContext *security.SecurityContext) (bool, []string, error) {
+			if *skipAllSecurity {
+				return true, nil, nil
+			}
 
-	// Check if security should run (not disabled by flag OR config)
-	shouldRunSecurity := !skipSecurity && app.Config.Settings.Security != nil && app.Config.Settings.Security.Enabled
-
-	if shouldRunSecurity {
-		securityContext := security.NewSecurityContext(app.Config.Settings.Security)
-
-		fmt.Printf("%s\n", scanningStyle.Render(fmt.Sprintf(" Running security scan for '%s'...", app.Dotfile.Name)))
-		securityReport, err := securityContext.ScanPath(sourcePath, ignores)
-		if err != nil {
-			fmt.Printf("Security scan error: %v\n", err)
-			return false
-		}
-
-		// Handle security findings
-		if securityReport.TotalFindings > 0 {
-			// Display the security report using UI styling
-			DisplaySecurityReport(securityReport)
+			fmt.Printf("\n%s\n", boldStyle.Render(fmt.Sprintf("Security findings in '%s':", report.Name)))
+			DisplaySecurityReport(report.Security)
 
-			proceed, skipAll, err := securityContext.InteractivePrompt(securityReport)
+			proceed, skipAll, skipFiles, err := securityDecision(a, securityContext, report.Security)
 			if err != nil {
-				fmt.Printf("Error handling security prompt: %v\n", err)
-				return false
+				return false, nil, err
 			}
-
-			// Set skipAll flag if user chose "skip all"
-			if skipAll && skipAllSecurity != nil {
-				*skipAllSecurity = true
-			}
-
-			if !proceed {
-				fmt.Printf("Sync aborted due to security concerns.\n")
-				return false
+			*skipAllSecurity = skipAll
+			if !proceed && !a.NonInteractive() {
+				return false, nil, app.ErrSyncAborted
 			}
-		} else {
-			fmt.Printf("%s\n", securityPassStyle.Render("󰸞 Security scan passed - no issues found."))
-		}
+			return proceed, skipFiles, nil
+		},
+		ResolveConflicts: func(report *app.SyncReport) (string, error) {
+			fmt.Println()
+			return conflictResolution(a, report.Name, report.Conflicts)
+		},
 	}
-
-	// In reverse sync mode, check that store file exists before doing diff
-	if app.Config.Settings.ReverseSyncMode {
-		if _, err := os.Stat(storePath); os.IsNotExist(err) {
-			fmt.Printf("%s\n", errorStyle.Render(fmt.Sprintf("Store file doesn't exist for reverse sync: %s", storePath)))
-			return false
-		}
+}
+
+// securityDecision asks what to do with the findings of a security scan, or applies the prompt
+// policy in non-interactive mode. It returns whether to proceed, whether to skip the scan of
+// the remaining dotfiles and the files with findings to leave out.
+func securityDecision(a *app.App, securityContext *security.SecurityContext, report *security.ScanReport) (bool, bool, []string, error) {
+	if a.NonInteractive() {
+		proceed, skipFiles, err := securityContext.PolicyDecision(report, a.PromptPolicy)
+		return proceed, false, skipFiles, err
 	}
-
-	// Check for changes before copying
-	changed, err := system.DiffFiles(sourcePath, storePath, ignores, app.Config.Settings.ReverseSyncMode)
-	if err != nil {
-		fmt.Printf("Error comparing dotfiles: %v\n", err)
-		return false
+	proceed, skipAll, err := securityContext.InteractivePrompt(report)
+	return proceed, skipAll, nil, err
+}
+
+// displaySkippedFiles lists the files left out because of their security findings
+func displaySkippedFiles(files []string) {
+	if len(files) == 0 {
+		return
 	}
-
-	if changed {
-		// Determine sync direction and display appropriate message
-		direction := "Updating store"
-		if app.Config.Settings.ReverseSyncMode {
-			direction = "Updating local config"
-		}
-		fmt.Print(changedStyle.Render(fmt.Sprintf("Changes detected in '%s'. %s...", app.Dotfile.Name, direction)))
-
-		var err error
-		var fromPath, toPath string
-		
-		// Swap paths based on reverse sync mode
-		if app.Config.Settings.ReverseSyncMode {
-			// Pull mode: copy from store to local config
-			fromPath = storePath
-			toPath = sourcePath
-		} else {
-			// Push mode: copy from local config to store
-			fromPath = sourcePath
-			toPath = storePath
-		}
-		
-		// Determine if source is directory (use fromPath not sourcePath)
-		fromInfo, err := os.Stat(fromPath)
-		if err != nil {
-			fmt.Printf(" %s\n", errorStyle.Render(fmt.Sprintf("Error accessing source: %v", err)))
-			return false
-		}
-		
-		if fromInfo.IsDir() {
-			err = system.CopyDirectory(fromPath, toPath, ignores)
-		} else {
-			err = os.MkdirAll(filepath.Dir(toPath), 0755)
-			if err == nil {
-				err = system.CopyFile(fromPath, toPath, ignores)
-			}
-		}
-
-		if err != nil {
-			fmt.Printf(" %s\n", errorStyle.Render(fmt.Sprintf("Error: %v", err)))
-			return false
-		}
-
-		// Only commit changes if we're in push mode (not reverse sync)
-		if !app.Config.Settings.ReverseSyncMode {
-			if err := app.GitCommitChanges("Update", app.Dotfile.Name); err != nil {
-				fmt.Printf(" %s\n", errorStyle.Render(fmt.Sprintf("Error committing changes: %v", err)))
-				return false
-			}
-		}
-
-		fmt.Printf(" %s\n", successStyle.Render("Success!"))
-	} else {
-		location := "in store"
-		if app.Config.Settings.ReverseSyncMode {
-			location = "in local config"
-		}
-		fmt.Println(unchangedStyle.Render(fmt.Sprintf("No changes detected %s for '%s'.", location, app.Dotfile.Name)))
+	fmt.Println(alertStyle.Render(fmt.Sprintf("Skipping %d file(s) with security findings:", len(files))))
+	for _, file := range files {
+		fmt.Printf("  %s\n", file)
 	}
-	return true
-}
\ No newline at end of file
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/bnema/gart/internal/app"
 	"github.com/bnema/gart/internal/config"
 	"github.com/bnema/gart/internal/security"
+	"github.com/bnema/gart/internal/system"
 )
 
 func TestRunSyncView_ReverseSyncMode(t *testing.T) {
@@ -207,7 +208,8 @@ func TestRunSyncView_ReverseSyncMode(t *testing.T) {
 						return err
 					}
 				}
-				return nil
+				// Forget the sync state recorded by previous cases
+				return resetManifest(storeDir)
 			}
 
 			if err := cleanDirs(); err != nil {
@@ -227,6 +229,7 @@ func TestRunSyncView_ReverseSyncMode(t *testing.T) {
 				},
 				StoragePath: storeDir,
 				Config: &config.Config{
+					Dotfiles: map[string]string{"test-dotfile": sourceDir},
 					Settings: config.SettingsConfig{
 						ReverseSyncMode: tt.reverseSyncMode,
 						GitVersioning:   false, // Disable git for testing
@@ -238,7 +241,7 @@ func TestRunSyncView_ReverseSyncMode(t *testing.T) {
 			}
 
 			// Capture output to verify status messages
-			result := RunSyncView(testApp, []string{}, tt.skipSecurity, nil)
+			result := RunSyncView(testApp, "test-dotfile", tt.skipSecurity, nil)
 
 			// Check result matches expectation
 			if result != tt.wantResult {
@@ -315,6 +318,9 @@ func TestRunSyncView_StatusMessages(t *testing.T) {
 					t.Fatalf("Failed to create directory: %v", err)
 				}
 			}
+			if err := resetManifest(storeDir); err != nil {
+				t.Fatalf("Failed to reset manifest: %v", err)
+			}
 
 			// Setup test case
 			if err := tt.setupFunc(); err != nil {
@@ -329,6 +335,7 @@ func TestRunSyncView_StatusMessages(t *testing.T) {
 				},
 				StoragePath: storeDir,
 				Config: &config.Config{
+					Dotfiles: map[string]string{"test-dotfile": sourceDir},
 					Settings: config.SettingsConfig{
 						ReverseSyncMode: tt.reverseSyncMode,
 						GitVersioning:   false,
@@ -342,7 +349,7 @@ func TestRunSyncView_StatusMessages(t *testing.T) {
 			// Note: This is a simplified test - in a real scenario, you'd want to capture
 			// the actual output and verify it contains the expected message.
 			// For now, we just verify the function runs without error
-			result := RunSyncView(testApp, []string{}, true, nil)
+			result := RunSyncView(testApp, "test-dotfile", true, nil)
 			if !result {
 				t.Errorf("RunSyncView() should have succeeded but returned false")
 			}
@@ -412,6 +419,9 @@ func TestRunSyncView_NoChanges(t *testing.T) {
 					t.Fatalf("Failed to create directory: %v", err)
 				}
 			}
+			if err := resetManifest(storeDir); err != nil {
+				t.Fatalf("Failed to reset manifest: %v", err)
+			}
 
 			// Setup test case
 			if err := tt.setupFunc(); err != nil {
@@ -426,6 +436,7 @@ func TestRunSyncView_NoChanges(t *testing.T) {
 				},
 				StoragePath: storeDir,
 				Config: &config.Config{
+					Dotfiles: map[string]string{"test-dotfile": sourceDir},
 					Settings: config.SettingsConfig{
 						ReverseSyncMode: tt.reverseSyncMode,
 						GitVersioning:   false,
@@ -437,7 +448,7 @@ func TestRunSyncView_NoChanges(t *testing.T) {
 			}
 
 			// Run sync - should return true but not make any changes
-			result := RunSyncView(testApp, []string{}, true, nil)
+			result := RunSyncView(testApp, "test-dotfile", true, nil)
 			if !result {
 				t.Errorf("RunSyncView() should have succeeded but returned false")
 			}
@@ -548,7 +559,7 @@ func TestRunSyncView_SingleFileReverseSyncMode(t *testing.T) {
 				if err := os.MkdirAll(storeDir, 0755); err != nil {
 					return err
 				}
-				return nil
+				return resetManifest(storeDir)
 			}
 
 			if err := cleanFiles(); err != nil {
@@ -568,6 +579,7 @@ func TestRunSyncView_SingleFileReverseSyncMode(t *testing.T) {
 				},
 				StoragePath: storeDir,
 				Config: &config.Config{
+					Dotfiles: map[string]string{"test-dotfile": sourceFile},
 					Settings: config.SettingsConfig{
 						ReverseSyncMode: tt.reverseSyncMode,
 						GitVersioning:   false, // Disable git for testing
@@ -579,7 +591,7 @@ func TestRunSyncView_SingleFileReverseSyncMode(t *testing.T) {
 			}
 
 			// Run sync
-			result := RunSyncView(testApp, []string{}, tt.skipSecurity, nil)
+			result := RunSyncView(testApp, "test-dotfile", tt.skipSecurity, nil)
 
 			// Check result matches expectation
 			if result != tt.wantResult {
@@ -675,7 +687,7 @@ func TestRunSyncView_SkipAllFunctionality(t *testing.T) {
 			skipAllFlag := tt.initialSkipAll
 			
 			// Run sync with skipAll pointer
-			result := RunSyncView(testApp, []string{}, false, &skipAllFlag)
+			result := RunSyncView(testApp, "test-dotfile", false, &skipAllFlag)
 			
 			// Should succeed
 			if !result {
@@ -751,6 +763,9 @@ func TestRunSyncView_SecurityMessages(t *testing.T) {
 					t.Fatalf("Failed to create directory: %v", err)
 				}
 			}
+			if err := resetManifest(storeDir); err != nil {
+				t.Fatalf("Failed to reset manifest: %v", err)
+			}
 
 			// Create a file that will cause changes (to trigger sync)
 			if err := os.WriteFile(filepath.Join(sourceDir, "test.conf"), []byte("test content"), 0644); err != nil {
@@ -765,6 +780,7 @@ func TestRunSyncView_SecurityMessages(t *testing.T) {
 				},
 				StoragePath: storeDir,
 				Config: &config.Config{
+					Dotfiles: map[string]string{"test-dotfile": sourceDir},
 					Settings: config.SettingsConfig{
 						ReverseSyncMode: false,
 						GitVersioning:   false,
@@ -779,7 +795,7 @@ func TestRunSyncView_SecurityMessages(t *testing.T) {
 			// the presence or absence of security messages.
 			// For now, we verify that the function behavior is correct
 			// Pass nil for skipAllSecurity since this is testing single operations
-			result := RunSyncView(testApp, []string{}, tt.skipSecurity, nil)
+			result := RunSyncView(testApp, "test-dotfile", tt.skipSecurity, nil)
 			
 			// The function should always succeed in this test case
 			if !result {
@@ -799,4 +815,252 @@ func TestRunSyncView_SecurityMessages(t *testing.T) {
 			// what messages were displayed.
 		})
 	}
-}
\ No newline at end of file
+}
+
+// resetManifest removes the manifest kept next to a test store
+func resetManifest(storeDir string) error {
+	if err := os.Remove(storeDir + ".manifest.toml"); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func TestRunSyncView_Conflicts(t *testing.T) {
+	tests := []struct {
+		name        string
+		resolution  string
+		wantResult  bool
+		wantLocal   string
+		wantStore   string
+		wantMarkers bool
+	}{
+		{name: "refuses to overwrite without a resolution", resolution: "", wantResult: false, wantLocal: "local edit\n", wantStore: "store edit\n"},
+		{name: "keep local", resolution: app.ResolveLocal, wantResult: true, wantLocal: "local edit\n", wantStore: "local edit\n"},
+		{name: "keep store", resolution: app.ResolveStore, wantResult: true, wantLocal: "store edit\n", wantStore: "store edit\n"},
+		{name: "write markers", resolution: app.ResolveMarkers, wantResult: true, wantStore: "store edit\n", wantMarkers: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			sourceDir := filepath.Join(tempDir, "source")
+			storeDir := filepath.Join(tempDir, "store")
+			localFile := filepath.Join(sourceDir, "init.lua")
+			storeFile := filepath.Join(storeDir, "test-dotfile", "init.lua")
+
+			if err := os.MkdirAll(sourceDir, 0755); err != nil {
+				t.Fatalf("Failed to create source directory: %v", err)
+			}
+			if err := os.WriteFile(localFile, []byte("base\n"), 0644); err != nil {
+				t.Fatalf("Failed to write local file: %v", err)
+			}
+
+			testApp := &app.App{
+				Dotfile:     app.Dotfile{Name: "test-dotfile", Path: sourceDir},
+				StoragePath: storeDir,
+				Config: &config.Config{
+					Dotfiles: map[string]string{"test-dotfile": sourceDir},
+					Settings: config.SettingsConfig{
+						Security: &security.SecurityConfig{Enabled: false},
+					},
+				},
+			}
+
+			// First sync records the base version of both sides
+			if !RunSyncView(testApp, "test-dotfile", true, nil) {
+				t.Fatalf("Initial sync failed")
+			}
+
+			if err := os.WriteFile(localFile, []byte("local edit\n"), 0644); err != nil {
+				t.Fatalf("Failed to edit local file: %v", err)
+			}
+			if err := os.WriteFile(storeFile, []byte("store edit\n"), 0644); err != nil {
+				t.Fatalf("Failed to edit store file: %v", err)
+			}
+
+			testApp.ConflictResolution = tt.resolution
+			if result := RunSyncView(testApp, "test-dotfile", true, nil); result != tt.wantResult {
+				t.Errorf("RunSyncView() result = %v, want %v", result, tt.wantResult)
+			}
+			if tt.wantMarkers {
+				// Keeping the local version is refused while markers are left in it
+				testApp.ConflictResolution = app.ResolveLocal
+				if RunSyncView(testApp, "test-dotfile", true, nil) {
+					t.Errorf("RunSyncView() should refuse to push unresolved conflict markers")
+				}
+			}
+
+			local, _ := os.ReadFile(localFile)
+			store, _ := os.ReadFile(storeFile)
+			if tt.wantMarkers {
+				if !system.HasConflictMarkers(local) {
+					t.Errorf("Local file should contain conflict markers, got %q", local)
+				}
+				if _, err := os.Stat(localFile + ".orig"); !os.IsNotExist(err) {
+					t.Errorf("No .orig file should be left in the local tree")
+				}
+				backups, err := testApp.ListBackups()
+				if err != nil || len(backups) != 1 || backups[0].Reason != "conflict" {
+					t.Errorf("The local version should be backed up, got %+v (%v)", backups, err)
+				}
+			} else if string(local) != tt.wantLocal {
+				t.Errorf("Local file = %q, want %q", local, tt.wantLocal)
+			}
+			if string(store) != tt.wantStore {
+				t.Errorf("Store file = %q, want %q", store, tt.wantStore)
+			}
+		})
+	}
+}
+
+func TestRunSyncView_OneSidedChanges(t *testing.T) {
+	tests := []struct {
+		name       string
+		reverse    bool
+		resolution string
+		wantBoth   string
+	}{
+		{name: "push takes store-only changes", reverse: false},
+		{name: "pull takes local-only changes", reverse: true},
+		{name: "resolution only applies to conflicts", reverse: false, resolution: app.ResolveStore, wantBoth: "store both\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			sourceDir := filepath.Join(tempDir, "source")
+			storeDir := filepath.Join(tempDir, "store")
+			storeEntry := filepath.Join(storeDir, "test-dotfile")
+
+			if err := os.MkdirAll(sourceDir, 0755); err != nil {
+				t.Fatalf("Failed to create source directory: %v", err)
+			}
+			for _, file := range []string{"store.lua", "local.lua", "both.lua"} {
+				if err := os.WriteFile(filepath.Join(sourceDir, file), []byte("base\n"), 0644); err != nil {
+					t.Fatalf("Failed to write local file: %v", err)
+				}
+			}
+
+			testApp := &app.App{
+				Dotfile:     app.Dotfile{Name: "test-dotfile", Path: sourceDir},
+				StoragePath: storeDir,
+				Config: &config.Config{
+					Dotfiles: map[string]string{"test-dotfile": sourceDir},
+					Settings: config.SettingsConfig{
+						Security: &security.SecurityConfig{Enabled: false},
+					},
+				},
+			}
+			if !RunSyncView(testApp, "test-dotfile", true, nil) {
+				t.Fatalf("Initial sync failed")
+			}
+
+			edits := map[string]string{
+				filepath.Join(storeEntry, "store.lua"): "store edit\n",
+				filepath.Join(sourceDir, "local.lua"):  "local edit\n",
+			}
+			if tt.resolution != "" {
+				edits[filepath.Join(storeEntry, "both.lua")] = "store both\n"
+				edits[filepath.Join(sourceDir, "both.lua")] = "local both\n"
+			}
+			for path, content := range edits {
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatalf("Failed to edit %s: %v", path, err)
+				}
+			}
+
+			testApp.Config.Settings.ReverseSyncMode = tt.reverse
+			testApp.ConflictResolution = tt.resolution
+			if !RunSyncView(testApp, "test-dotfile", true, nil) {
+				t.Fatalf("RunSyncView() failed")
+			}
+
+			// Each side keeps the change it made, whatever the direction
+			want := map[string]string{"store.lua": "store edit\n", "local.lua": "local edit\n"}
+			if tt.wantBoth != "" {
+				want["both.lua"] = tt.wantBoth
+			}
+			for file, content := range want {
+				for _, root := range []string{sourceDir, storeEntry} {
+					got, _ := os.ReadFile(filepath.Join(root, file))
+					if string(got) != content {
+						t.Errorf("%s = %q, want %q", filepath.Join(root, file), got, content)
+					}
+				}
+			}
+
+			if !tt.reverse && tt.resolution == "" {
+				backups, err := testApp.ListBackups()
+				if err != nil || len(backups) == 0 || backups[len(backups)-1].Reason != "sync" {
+					t.Errorf("The overwritten local file should be backed up, got %+v (%v)", backups, err)
+				}
+			}
+		})
+	}
+}
+
+func TestRunSyncView_ProfilePathOverride(t *testing.T) {
+	tempDir := t.TempDir()
+	storeDir := filepath.Join(tempDir, "store")
+	homeConfig := filepath.Join(tempDir, "home", ".gitconfig")
+	workConfig := filepath.Join(tempDir, "work", ".gitconfig-work")
+	for _, dir := range []string{storeDir, filepath.Dir(homeConfig), filepath.Dir(workConfig)} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+	}
+	if err := os.WriteFile(homeConfig, []byte("[user]\n\tname = home\n"), 0644); err != nil {
+		t.Fatalf("Failed to write home config: %v", err)
+	}
+	if err := os.WriteFile(workConfig, []byte("[user]\n\tname = work\n"), 0644); err != nil {
+		t.Fatalf("Failed to write work config: %v", err)
+	}
+
+	// Both hosts share the store, the work host overrides the path of the dotfile
+	newHost := func(reverse bool) *app.App {
+		return &app.App{
+			StoragePath: storeDir,
+			Config: &config.Config{
+				Dotfiles: map[string]string{"git": homeConfig},
+				Profiles: map[string]config.Profile{
+					"work": {Paths: map[string]string{"git": workConfig}},
+				},
+				Settings: config.SettingsConfig{
+					ReverseSyncMode: reverse,
+					Backup:          &config.BackupConfig{Enabled: false},
+				},
+			},
+		}
+	}
+	sync := func(host *app.App) {
+		if !RunSyncView(host, "git", true, nil) {
+			t.Fatalf("RunSyncView() failed for %s", host.Config.Dotfiles["git"])
+		}
+	}
+
+	sync(newHost(false))
+
+	work := newHost(false)
+	if err := work.ActivateProfile("work"); err != nil {
+		t.Fatalf("Failed to activate profile: %v", err)
+	}
+	sync(work)
+
+	if _, err := os.Stat(filepath.Join(storeDir, "git.gitconfig-work")); !os.IsNotExist(err) {
+		t.Errorf("The override must sync the store entry of the base path")
+	}
+
+	// The home host gets the changes pushed from the work host. Each host keeps its own
+	// manifest next to its clone of the store.
+	if err := resetManifest(storeDir); err != nil {
+		t.Fatalf("Failed to reset manifest: %v", err)
+	}
+	sync(newHost(true))
+	content, err := os.ReadFile(homeConfig)
+	if err != nil {
+		t.Fatalf("Failed to read home config: %v", err)
+	}
+	if string(content) != "[user]\n\tname = work\n" {
+		t.Errorf("Home config = %q, want the work content", content)
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/bnema/gart/internal/app"
 	"github.com/bnema/gart/internal/cmd"
@@ -15,13 +16,13 @@ func main() {
 	_, configPath, err := system.GetConfigPaths()
 	if err != nil {
 		fmt.Println("Error getting config paths:", err)
-		return
+		os.Exit(1)
 	}
 
-	cfg, _, err := checkFirstLaunch(configPath)
+	cfg, _, err := checkFirstLaunch(configPath, promptPolicyFromArgs(os.Args[1:]))
 	if err != nil {
 		fmt.Printf("Error during configuration check: %v\n", err)
-		return
+		os.Exit(1)
 	}
 
 	app := &app.App{
@@ -33,7 +34,7 @@ func main() {
 	// Ensure the storage path exists
 	if err := os.MkdirAll(app.StoragePath, 0755); err != nil {
 		fmt.Printf("Error creating storage directory: %v\n", err)
-		return
+		os.Exit(1)
 	}
 
 	// If Git versioning is enabled, check if the repo exists and initialize if necessary
@@ -42,19 +43,19 @@ func main() {
 		repo, err := git.NewRepository(app.StoragePath)
 		if err != nil {
 			fmt.Printf("Error creating git repository: %v\n", err)
-			return
+			os.Exit(1)
 		}
 
 		gitRepoExists, err := repo.Exists()
 		if err != nil {
 			fmt.Printf("Error checking Git repository: %v\n", err)
-			return
+			os.Exit(1)
 		}
 
 		if !gitRepoExists {
 			if err := initializeGitRepo(app, repo); err != nil {
 				fmt.Println(err)
-				return
+				os.Exit(1)
 			}
 		}
 	}
@@ -63,7 +64,7 @@ func main() {
 	err = app.LoadConfig()
 	if err != nil {
 		fmt.Println("Error loading the config:", err)
-		return
+		os.Exit(1)
 	}
 
 	// Begin command execution
@@ -88,25 +89,66 @@ func initializeGitRepo(app *app.App, repo git.GitRepository) error {
 	return nil
 }
 
-// checkFirstLaunch checks if this is the first launch and prompts the user for Git versioning
-func checkFirstLaunch(configPath string) (*config.Config, bool, error) {
+// promptPolicyFromArgs returns the prompt policy requested on the command line, which cobra
+// only parses after the first launch checks, or an empty policy when prompts are allowed
+func promptPolicyFromArgs(args []string) system.PromptPolicy {
+	nonInteractive := false
+	policy := ""
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		switch {
+		case arg == "-y" || arg == "--yes":
+			return system.PromptProceed
+		case arg == "--non-interactive":
+			nonInteractive = true
+		case arg == "--prompt-policy" && i+1 < len(args):
+			policy = args[i+1]
+		case strings.HasPrefix(arg, "--prompt-policy="):
+			policy = strings.TrimPrefix(arg, "--prompt-policy=")
+		}
+	}
+
+	if !nonInteractive && policy == "" {
+		return ""
+	}
+	parsed, err := system.ParsePromptPolicy(policy)
+	if err != nil {
+		return system.PromptFail
+	}
+	return parsed
+}
+
+// checkFirstLaunch checks if this is the first launch and prompts the user for Git versioning,
+// or answers with the prompt policy when one is given
+func checkFirstLaunch(configPath string, policy system.PromptPolicy) (*config.Config, bool, error) {
 	// Try to load the existing config
 	cfg, err := config.LoadConfig(configPath)
 	if err != nil {
 		// If the error is because the file doesn't exist, create a default config
 		if os.IsNotExist(err) {
+			// Prompt for Git versioning
+			var enableGit bool
+			switch policy {
+			case "":
+				if enableGit, err = system.PromptForGitVersioning(); err != nil {
+					return nil, false, fmt.Errorf("error prompting for Git versioning: %w", err)
+				}
+			case system.PromptProceed:
+				enableGit = true
+			case system.PromptSkip:
+				enableGit = false
+			default:
+				return nil, false, fmt.Errorf("git versioning must be chosen on first launch: %w", system.ErrInputRequired)
+			}
+
 			// Create a default config
 			cfg, err = config.CreateDefaultConfig()
 			if err != nil {
 				return nil, false, fmt.Errorf("error creating default config: %w", err)
 			}
 
-			// Prompt for Git versioning
-			enableGit, err := system.PromptForGitVersioning()
-			if err != nil {
-				return nil, false, fmt.Errorf("error prompting for Git versioning: %w", err)
-			}
-
 			cfg.Settings.GitVersioning = enableGit
 
 			// Save the config with the user's Git versioning preference